@@ -28,7 +28,7 @@ CREATE TABLE IF NOT EXISTS `divination_records` (
   `user_id` bigint NOT NULL COMMENT '用户ID',
   `type` varchar(20) NOT NULL COMMENT '占卜类型',
   `question` text NOT NULL COMMENT '问题内容',
-  `result` text NOT NULL COMMENT '占卜结果',
+  `result` mediumtext NOT NULL COMMENT '占卜结果(JSON)',
   `ai_analysis` text COMMENT 'AI分析结果',
   `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
   `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
@@ -69,5 +69,21 @@ CREATE TABLE IF NOT EXISTS `user_favorites` (
   CONSTRAINT `fk_favorite_record` FOREIGN KEY (`record_id`) REFERENCES `divination_records` (`id`) ON DELETE CASCADE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户收藏表';
 
+-- 塔罗自定义牌组表
+CREATE TABLE IF NOT EXISTS `tarot_decks` (
+  `id` bigint NOT NULL AUTO_INCREMENT COMMENT '牌组ID',
+  `user_id` bigint NOT NULL COMMENT '用户ID',
+  `name` varchar(50) NOT NULL COMMENT '牌组名称',
+  `description` text COMMENT '牌组说明',
+  `cards` mediumtext NOT NULL COMMENT '牌列表(JSON)',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  `deleted_at` timestamp NULL DEFAULT NULL COMMENT '删除时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_user_id` (`user_id`),
+  KEY `idx_deleted_at` (`deleted_at`),
+  CONSTRAINT `fk_tarot_deck_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='塔罗自定义牌组表';
+
 -- 重新启用外键检查
 SET FOREIGN_KEY_CHECKS = 1;
\ No newline at end of file
@@ -0,0 +1,116 @@
+package almanac
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hobbyqhd/yijing/service/models"
+)
+
+// Activities 黄历宜忌所用的事项
+var Activities = []string{
+	"祭祀", "祈福", "嫁娶", "订婚", "出行", "搬家", "入宅", "开业", "交易", "签约",
+	"纳财", "动土", "修造", "安床", "栽种", "求医", "入学", "上任", "安葬", "诉讼",
+	"沐浴", "理发", "开仓", "掘井", "求嗣", "会友",
+}
+
+// officerActivities 建除十二神的宜忌，据《协纪辨方书》建除诸神所宜所忌归并为常用事项
+var officerActivities = map[string][2][]string{
+	"建": {{"出行", "上任", "祈福", "会友", "求嗣"}, {"动土", "开仓", "掘井", "安葬"}},
+	"除": {{"祭祀", "沐浴", "求医", "理发", "修造"}, {"嫁娶", "搬家", "出行"}},
+	"满": {{"祭祀", "祈福", "开业", "交易", "纳财", "签约"}, {"动土", "栽种", "上任", "求医"}},
+	"平": {{"修造", "祭祀", "沐浴"}, {"栽种", "掘井", "开业"}},
+	"定": {{"嫁娶", "订婚", "入宅", "交易", "祭祀", "签约", "入学"}, {"诉讼", "出行", "求医"}},
+	"执": {{"祭祀", "纳财", "修造", "栽种"}, {"开业", "出行", "搬家", "开仓"}},
+	"破": {{"求医", "沐浴"}, {"嫁娶", "订婚", "开业", "入宅", "搬家", "出行", "签约", "动土", "安床"}},
+	"危": {{"祭祀", "祈福", "安床"}, {"出行", "嫁娶", "搬家", "动土"}},
+	"成": {{"嫁娶", "订婚", "开业", "入学", "上任", "入宅", "搬家", "交易", "签约", "出行"}, {"诉讼"}},
+	"收": {{"纳财", "入学", "栽种", "交易"}, {"出行", "安葬", "上任"}},
+	"开": {{"开业", "嫁娶", "入学", "出行", "上任", "求医", "搬家", "入宅"}, {"安葬", "动土"}},
+	"闭": {{"安葬", "修造", "纳财"}, {"开业", "出行", "求医", "上任", "搬家"}},
+}
+
+// pengzuAvoid 彭祖百忌所忌之事在常用事项中的对应
+var pengzuAvoid = map[string]string{
+	"甲": "开仓", "乙": "栽种", "丙": "修造", "丁": "理发", "己": "签约", "壬": "掘井", "癸": "诉讼",
+	"丑": "上任", "寅": "祭祀", "卯": "掘井", "辰": "安葬", "巳": "出行", "午": "修造", "未": "求医",
+	"申": "安床", "亥": "嫁娶",
+}
+
+// majorActivities 须择黄道日而行的大事，黑道日不取为宜
+var majorActivities = []string{"嫁娶", "开业", "入宅", "搬家", "动土", "安葬"}
+
+// dayActivities 定当日宜忌：以建除十二神为本，彭祖百忌所忌者移入忌，
+// 黑道日大事不取为宜，破日诸事不宜
+func dayActivities(a *Almanac) (suitable, avoid, notes []string) {
+	table := officerActivities[a.Officer]
+	suitable = append([]string{}, table[0]...)
+	avoid = append([]string{}, table[1]...)
+	notes = []string{fmt.Sprintf("%s日：宜%s，忌%s", a.Officer, strings.Join(table[0], "、"), strings.Join(table[1], "、"))}
+
+	for i, key := range [2]string{string(a.DayStem), string(a.DayBranch)} {
+		if activity, ok := pengzuAvoid[key]; ok {
+			suitable = remove(suitable, activity)
+			avoid = appendUnique(avoid, activity)
+			notes = append(notes, fmt.Sprintf("彭祖百忌“%s”，忌%s", a.Pengzu[i], activity))
+		}
+	}
+
+	if !a.DaySpirit.Yellow {
+		dropped := make([]string, 0)
+		for _, activity := range majorActivities {
+			if contains(suitable, activity) {
+				suitable = remove(suitable, activity)
+				dropped = append(dropped, activity)
+			}
+		}
+		if len(dropped) > 0 {
+			notes = append(notes, fmt.Sprintf("%s黑道日，%s等大事不宜", a.DaySpirit.Name, strings.Join(dropped, "、")))
+		}
+	}
+	if a.Officer == "破" {
+		notes = append(notes, "破日为月破，大事不宜")
+	}
+	return suitable, avoid, notes
+}
+
+// Suits 当日是否宜做某事
+func (a *Almanac) Suits(activity string) bool {
+	return contains(a.Suitable, activity)
+}
+
+// Avoids 当日是否忌做某事
+func (a *Almanac) Avoids(activity string) bool {
+	return contains(a.Avoid, activity)
+}
+
+// ClashesWith 当日地支是否冲某一地支（如生年、日柱之支）
+func (a *Almanac) ClashesWith(branch models.Branch) bool {
+	return mod(a.DayBranch.Index()-branch.Index(), 12) == 6
+}
+
+func contains(list []string, item string) bool {
+	for _, s := range list {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
+
+func remove(list []string, item string) []string {
+	result := list[:0]
+	for _, s := range list {
+		if s != item {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func appendUnique(list []string, item string) []string {
+	if contains(list, item) {
+		return list
+	}
+	return append(list, item)
+}
@@ -0,0 +1,181 @@
+// Package almanac 推算黄历：建除十二神、二十八宿、冲煞、彭祖百忌、
+// 宜忌事项及十二时辰的黄道黑道吉凶
+package almanac
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hobbyqhd/yijing/service/calendar"
+	"github.com/hobbyqhd/yijing/service/models"
+)
+
+// Almanac 一日的黄历
+type Almanac struct {
+	Calendar    *calendar.DayInfo `json:"calendar"`     // 公历、农历、干支与节气
+	Officer     string            `json:"officer"`      // 建除十二神
+	Mansion     Mansion           `json:"mansion"`      // 二十八宿
+	DaySpirit   Spirit            `json:"day_spirit"`   // 值日的黄道、黑道神
+	Clash       Clash             `json:"clash"`        // 冲煞
+	Pengzu      [2]string         `json:"pengzu"`       // 彭祖百忌（天干、地支各一句）
+	Suitable    []string          `json:"suitable"`     // 宜
+	Avoid       []string          `json:"avoid"`        // 忌
+	Notes       []string          `json:"notes"`        // 宜忌的取舍依据
+	Hours       []HourAlmanac     `json:"hours"`        // 十二时辰吉凶
+	DayStem     models.Stem       `json:"day_stem"`     // 日干
+	DayBranch   models.Branch     `json:"day_branch"`   // 日支
+	MonthBranch models.Branch     `json:"month_branch"` // 月建（节气月之支）
+}
+
+// Mansion 值日星宿
+type Mansion struct {
+	Name    string `json:"name"`    // 宿名，如角
+	Full    string `json:"full"`    // 全称，如角木蛟
+	Quarter string `json:"quarter"` // 所属四象，如东方青龙
+	Lucky   bool   `json:"lucky"`   // 是否为吉宿
+}
+
+// Spirit 黄道、黑道十二神之一
+type Spirit struct {
+	Name   string `json:"name"`   // 神名，如青龙
+	Yellow bool   `json:"yellow"` // 是否为黄道
+}
+
+// Clash 冲煞：与当日（时）地支相冲的干支及生肖，煞所在的方位
+type Clash struct {
+	Ganzhi    string `json:"ganzhi"`    // 所冲干支，如戊午
+	Zodiac    string `json:"zodiac"`    // 所冲生肖
+	Direction string `json:"direction"` // 煞方
+}
+
+// HourAlmanac 一个时辰的吉凶
+type HourAlmanac struct {
+	Branch models.Branch `json:"branch"` // 时支
+	Ganzhi string        `json:"ganzhi"` // 时干支
+	Range  string        `json:"range"`  // 钟点，如23:00-00:59
+	Spirit Spirit        `json:"spirit"` // 黄道、黑道神
+	Clash  Clash         `json:"clash"`  // 时冲
+	Lucky  bool          `json:"lucky"`  // 吉时：黄道且不冲日支
+}
+
+// officers 建除十二神，月建之支所在之日为建
+var officers = [12]string{"建", "除", "满", "平", "定", "执", "破", "危", "成", "收", "开", "闭"}
+
+// spirits 黄道、黑道十二神，自青龙起
+var spirits = [12]Spirit{
+	{"青龙", true}, {"明堂", true}, {"天刑", false}, {"朱雀", false},
+	{"金匮", true}, {"天德", true}, {"白虎", false}, {"玉堂", true},
+	{"天牢", false}, {"玄武", false}, {"司命", true}, {"勾陈", false},
+}
+
+// mansions 二十八宿，自角宿起
+var mansions = [28]Mansion{
+	{"角", "角木蛟", "东方青龙", true}, {"亢", "亢金龙", "东方青龙", false}, {"氐", "氐土貉", "东方青龙", false},
+	{"房", "房日兔", "东方青龙", true}, {"心", "心月狐", "东方青龙", false}, {"尾", "尾火虎", "东方青龙", true},
+	{"箕", "箕水豹", "东方青龙", true}, {"斗", "斗木獬", "北方玄武", true}, {"牛", "牛金牛", "北方玄武", false},
+	{"女", "女土蝠", "北方玄武", false}, {"虚", "虚日鼠", "北方玄武", false}, {"危", "危月燕", "北方玄武", false},
+	{"室", "室火猪", "北方玄武", true}, {"壁", "壁水貐", "北方玄武", true}, {"奎", "奎木狼", "西方白虎", true},
+	{"娄", "娄金狗", "西方白虎", true}, {"胃", "胃土雉", "西方白虎", true}, {"昴", "昴日鸡", "西方白虎", false},
+	{"毕", "毕月乌", "西方白虎", true}, {"觜", "觜火猴", "西方白虎", false}, {"参", "参水猿", "西方白虎", true},
+	{"井", "井木犴", "南方朱雀", true}, {"鬼", "鬼金羊", "南方朱雀", false}, {"柳", "柳土獐", "南方朱雀", false},
+	{"星", "星日马", "南方朱雀", false}, {"张", "张月鹿", "南方朱雀", true}, {"翼", "翼火蛇", "南方朱雀", false},
+	{"轸", "轸水蚓", "南方朱雀", true},
+}
+
+// zodiacAnimals 十二生肖
+var zodiacAnimals = [12]string{"鼠", "牛", "虎", "兔", "龙", "蛇", "马", "羊", "猴", "鸡", "狗", "猪"}
+
+// shaDirections 三合局所煞的方位：申子辰煞南，寅午戌煞北，亥卯未煞西，巳酉丑煞东，按地支序数排列
+var shaDirections = [12]string{"南", "东", "北", "西", "南", "东", "北", "西", "南", "东", "北", "西"}
+
+// pengzuStems 彭祖百忌（天干）
+var pengzuStems = [10]string{
+	"甲不开仓财物耗散", "乙不栽植千株不长", "丙不修灶必见灾殃", "丁不剃头头必生疮", "戊不受田田主不祥",
+	"己不破券二比并亡", "庚不经络织机虚张", "辛不合酱主人不尝", "壬不汲水更难提防", "癸不词讼理弱敌强",
+}
+
+// pengzuBranches 彭祖百忌（地支）
+var pengzuBranches = [12]string{
+	"子不问卜自惹祸殃", "丑不冠带主不还乡", "寅不祭祀神鬼不尝", "卯不穿井水泉不香", "辰不哭泣必主重丧", "巳不远行财物伏藏",
+	"午不苫盖屋主更张", "未不服药毒气入肠", "申不安床鬼祟入房", "酉不会客醉坐颠狂", "戌不吃犬作怪上床", "亥不嫁娶不利新郎",
+}
+
+// Of 求公历某日（北京时间）的黄历
+func Of(year int, month time.Month, day int) (*Almanac, error) {
+	info, err := calendar.DayInfoOf(year, month, day)
+	if err != nil {
+		return nil, err
+	}
+	date := time.Date(year, month, day, 0, 0, 0, 0, calendar.ChinaZone)
+	// 交节当日即以新月论
+	_, monthBranch := calendar.MonthGanzhi(date.Add(24*time.Hour - time.Second))
+	dayStem, dayBranch := calendar.DayGanzhi(date)
+
+	a := &Almanac{
+		Calendar:    info,
+		Officer:     Officer(monthBranch, dayBranch),
+		Mansion:     MansionOf(year, month, day),
+		DaySpirit:   SpiritOf(monthBranch, dayBranch),
+		Clash:       clashOf(dayStem, dayBranch),
+		Pengzu:      [2]string{pengzuStems[dayStem.Index()], pengzuBranches[dayBranch.Index()]},
+		DayStem:     dayStem,
+		DayBranch:   dayBranch,
+		MonthBranch: monthBranch,
+	}
+	a.Suitable, a.Avoid, a.Notes = dayActivities(a)
+	a.Hours = hoursOf(dayStem, dayBranch)
+	return a, nil
+}
+
+// Officer 建除十二神：日支与月建相同为建，其后依次为除、满、平……
+func Officer(monthBranch, dayBranch models.Branch) string {
+	return officers[mod(dayBranch.Index()-monthBranch.Index(), 12)]
+}
+
+// SpiritOf 黄道黑道十二神。以月起日时，青龙所在：寅申月在子，卯酉在寅，辰戌在辰，
+// 巳亥在午，子午在申，丑未在戌；以日起时同理，将月支换为日支
+func SpiritOf(base, branch models.Branch) Spirit {
+	start := mod(base.Index()-2, 6) * 2
+	return spirits[mod(branch.Index()-start, 12)]
+}
+
+// MansionOf 值日星宿，二十八日一周，与七曜相配：角宿值日必为木曜（星期四）。
+// 儒略日数加十一模二十八即为自角宿起的序数
+func MansionOf(year int, month time.Month, day int) Mansion {
+	return mansions[mod(calendar.JulianDayNumber(year, month, day)+11, 28)]
+}
+
+// clashOf 冲煞：地支相冲，所冲之干为本干后第四位（如甲子冲戊午），煞方依三合局定
+func clashOf(stem models.Stem, branch models.Branch) Clash {
+	b := mod(branch.Index()+6, 12)
+	return Clash{
+		Ganzhi:    string(models.Stems[mod(stem.Index()+4, 10)]) + string(models.Branches[b]),
+		Zodiac:    zodiacAnimals[b],
+		Direction: shaDirections[branch.Index()],
+	}
+}
+
+// hoursOf 十二时辰：时干按五鼠遁由日干推出，黄道黑道以日支起
+func hoursOf(dayStem models.Stem, dayBranch models.Branch) []HourAlmanac {
+	hours := make([]HourAlmanac, 0, 12)
+	first := dayStem.Index() % 5 * 2 // 甲己还加甲，乙庚丙作初……
+	for i := 0; i < 12; i++ {
+		stem, branch := models.Stems[(first+i)%10], models.Branches[i]
+		spirit := SpiritOf(dayBranch, branch)
+		start := mod(2*i-1, 24)
+		hours = append(hours, HourAlmanac{
+			Branch: branch,
+			Ganzhi: string(stem) + string(branch),
+			Range:  fmt.Sprintf("%02d:00-%02d:59", start, (start+1)%24),
+			Spirit: spirit,
+			Clash:  clashOf(stem, branch),
+			Lucky:  spirit.Yellow && mod(branch.Index()-dayBranch.Index(), 12) != 6,
+		})
+	}
+	return hours
+}
+
+// mod 取非负余数
+func mod(a, n int) int {
+	return (a%n + n) % n
+}
@@ -0,0 +1,102 @@
+package astro
+
+import (
+	"fmt"
+	"math"
+)
+
+// SiderealTime 计算格林尼治视恒星时（度），jd为世界时儒略日，采用Meeus《天文算法》式12.4
+func SiderealTime(jd float64) float64 {
+	t := (jd - J2000) / 36525
+	mean := 280.46061837 + 360.98564736629*(jd-J2000) + 0.000387933*t*t - t*t*t/38710000
+	jde := ToDynamicalTime(jd)
+	deltaPsi, _ := Nutation(jde)
+	return normalizeDegrees(mean + deltaPsi/3600*cosDeg(Obliquity(jde)))
+}
+
+// atan2Deg 以角度表示的反正切，结果规范到[0, 360)
+func atan2Deg(y, x float64) float64 {
+	return normalizeDegrees(math.Atan2(y, x) * 180 / math.Pi)
+}
+
+// Angles 求上升点与天顶（中天）的黄经（度）。jd为世界时儒略日，
+// latitude、longitude为地理纬度与经度（北纬、东经为正）
+func Angles(jd, latitude, longitude float64) (ascendant, midheaven float64) {
+	ramc := normalizeDegrees(SiderealTime(jd) + longitude)
+	epsilon := Obliquity(ToDynamicalTime(jd))
+	midheaven = atan2Deg(sinDeg(ramc), cosDeg(ramc)*cosDeg(epsilon))
+	ascendant = atan2Deg(cosDeg(ramc), -(sinDeg(ramc)*cosDeg(epsilon) + math.Tan(latitude*math.Pi/180)*sinDeg(epsilon)))
+	return ascendant, midheaven
+}
+
+// MaxPlacidusLatitude 普拉西度分宫制可用的最大纬度，极圈内部分黄道不升不落，无法三分半弧
+const MaxPlacidusLatitude = 66
+
+// PlacidusCusps 普拉西度分宫制的十二宫起点黄经（度），下标0为第一宫。
+// 第十一、十二宫起点为昼半弧的三分点，第二、三宫起点为夜半弧的三分点，其余各宫取对宫
+func PlacidusCusps(jd, latitude, longitude float64) ([12]float64, error) {
+	var cusps [12]float64
+	if math.Abs(latitude) > MaxPlacidusLatitude {
+		return cusps, fmt.Errorf("纬度超过%d度，无法使用普拉西度分宫制", MaxPlacidusLatitude)
+	}
+	ramc := normalizeDegrees(SiderealTime(jd) + longitude)
+	epsilon := Obliquity(ToDynamicalTime(jd))
+	tanPhi := math.Tan(latitude * math.Pi / 180)
+
+	// cusp 求赤经自中天起算、等于半弧某一分数的黄道点；below表示在地平线下，按夜半弧计
+	cusp := func(fraction float64, below bool) float64 {
+		ra := ramc + fraction*90
+		if below {
+			ra = ramc + 180 - fraction*90
+		}
+		var lon float64
+		for i := 0; i < 50; i++ {
+			lon = atan2Deg(sinDeg(ra), cosDeg(ra)*cosDeg(epsilon))
+			dec := math.Asin(sinDeg(epsilon)*sinDeg(lon)) * 180 / math.Pi
+			semiArc := math.Acos(math.Max(-1, math.Min(1, -tanPhi*math.Tan(dec*math.Pi/180)))) * 180 / math.Pi
+			next := ramc + fraction*semiArc
+			if below {
+				next = ramc + 180 - fraction*(180-semiArc)
+			}
+			if math.Abs(math.Mod(next-ra+540, 360)-180) < 1e-9 {
+				break
+			}
+			ra = next
+		}
+		return lon
+	}
+
+	ascendant, midheaven := Angles(jd, latitude, longitude)
+	cusps[0] = ascendant
+	cusps[9] = midheaven
+	cusps[10] = cusp(1.0/3, false)
+	cusps[11] = cusp(2.0/3, false)
+	cusps[1] = cusp(2.0/3, true)
+	cusps[2] = cusp(1.0/3, true)
+	for _, i := range []int{0, 1, 2, 9, 10, 11} {
+		cusps[(i+6)%12] = normalizeDegrees(cusps[i] + 180)
+	}
+	return cusps, nil
+}
+
+// WholeSignCusps 整宫制的十二宫起点：上升点所在星座为第一宫，其后每个星座为一宫
+func WholeSignCusps(ascendant float64) [12]float64 {
+	var cusps [12]float64
+	first := math.Floor(ascendant/30) * 30
+	for i := range cusps {
+		cusps[i] = normalizeDegrees(first + float64(i)*30)
+	}
+	return cusps
+}
+
+// HouseOf 求黄经所落的宫位（1至12）
+func HouseOf(lon float64, cusps [12]float64) int {
+	for i := 0; i < 12; i++ {
+		start, end := cusps[i], cusps[(i+1)%12]
+		span := normalizeDegrees(end - start)
+		if normalizeDegrees(lon-start) < span {
+			return i + 1
+		}
+	}
+	return 1
+}
@@ -0,0 +1,69 @@
+package astro
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSiderealTime(t *testing.T) {
+	// Meeus《天文算法》例12.a：1987年4月10日0时世界时，视恒星时13时10分46.1351秒
+	want := (13 + 10.0/60 + 46.1351/3600) * 15
+	if got := SiderealTime(2446895.5); math.Abs(got-want) > 0.0001 {
+		t.Errorf("视恒星时得%.6f度，应为%.6f度", got, want)
+	}
+}
+
+func TestAngles(t *testing.T) {
+	// 爱因斯坦：1879年3月14日11时30分乌尔姆地方平时（北纬48度24分，东经10度），
+	// 通行星盘为上升巨蟹座11度38分、天顶双鱼座12度50分
+	jd := JulianDay(time.Date(1879, 3, 14, 10, 50, 0, 0, time.UTC))
+	ascendant, midheaven := Angles(jd, 48.4, 10)
+	if want := 90 + 11 + 38.0/60; math.Abs(ascendant-want) > 2.0/60 {
+		t.Errorf("上升点得%.4f，应为%.4f", ascendant, want)
+	}
+	if want := 330 + 12 + 50.0/60; math.Abs(midheaven-want) > 2.0/60 {
+		t.Errorf("天顶得%.4f，应为%.4f", midheaven, want)
+	}
+}
+
+func TestPlacidusCusps(t *testing.T) {
+	// J2000.0（2000年1月1日12时世界时）伦敦（北纬51.5度，经度0）
+	jd, latitude, longitude := 2451545.0, 51.5, 0.0
+	want := [12]float64{24.287, 61.162, 82.030, 99.611, 119.053, 147.687,
+		204.287, 241.162, 262.030, 279.611, 299.053, 327.687}
+	cusps, err := PlacidusCusps(jd, latitude, longitude)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range cusps {
+		if math.Abs(cusps[i]-want[i]) > 0.01 {
+			t.Errorf("第%d宫起点得%.3f，应为%.3f", i+1, cusps[i], want[i])
+		}
+	}
+
+	// 按定义复核：第十一、十二宫起点距中天为昼半弧的三分之一、三分之二，
+	// 第三、二宫起点距下中天为夜半弧的三分之一、三分之二
+	ramc := normalizeDegrees(SiderealTime(jd) + longitude)
+	epsilon := Obliquity(ToDynamicalTime(jd))
+	checks := []struct {
+		house    int
+		fraction float64
+		below    bool
+	}{{11, 1.0 / 3, false}, {12, 2.0 / 3, false}, {2, 2.0 / 3, true}, {3, 1.0 / 3, true}}
+	for _, c := range checks {
+		lon := cusps[c.house-1]
+		ra := atan2Deg(sinDeg(lon)*cosDeg(epsilon), cosDeg(lon))
+		dec := math.Asin(sinDeg(epsilon)*sinDeg(lon)) * 180 / math.Pi
+		semiArc := math.Acos(-math.Tan(latitude*math.Pi/180)*math.Tan(dec*math.Pi/180)) * 180 / math.Pi
+		distance := normalizeDegrees(ra - ramc)
+		expected := c.fraction * semiArc
+		if c.below {
+			distance = normalizeDegrees(ramc + 180 - ra)
+			expected = c.fraction * (180 - semiArc)
+		}
+		if math.Abs(distance-expected) > 0.001 {
+			t.Errorf("第%d宫起点距子午圈%.4f度，应为半弧的%.0f/3即%.4f度", c.house, distance, c.fraction*3, expected)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package astro
+
+import (
+	"math"
+	"time"
+)
+
+// unixEpochJD 1970-01-01T00:00:00Z 对应的儒略日
+const unixEpochJD = 2440587.5
+
+// J2000 J2000.0 历元（2000-01-01T12:00:00 TT）对应的儒略日
+const J2000 = 2451545.0
+
+// JulianDay 计算给定时刻的儒略日（世界时）
+func JulianDay(t time.Time) float64 {
+	t = t.UTC()
+	return float64(t.Unix())/86400.0 + float64(t.Nanosecond())/86400e9 + unixEpochJD
+}
+
+// TimeFromJulianDay 将儒略日（世界时）转换为UTC时刻，精确到秒
+func TimeFromJulianDay(jd float64) time.Time {
+	seconds := math.Round((jd - unixEpochJD) * 86400)
+	return time.Unix(int64(seconds), 0).UTC()
+}
+
+// DeltaT 估算力学时与世界时之差ΔT（秒），采用Espenak与Meeus的分段多项式
+func DeltaT(year float64) float64 {
+	switch {
+	case year < 1860:
+		u := (year - 1820) / 100
+		return -20 + 32*u*u
+	case year < 1900:
+		t := year - 1860
+		return 7.62 + 0.5737*t - 0.251754*t*t + 0.01680668*t*t*t -
+			0.0004473624*t*t*t*t + t*t*t*t*t/233174
+	case year < 1920:
+		t := year - 1900
+		return -2.79 + 1.494119*t - 0.0598939*t*t + 0.0061966*t*t*t - 0.000197*t*t*t*t
+	case year < 1941:
+		t := year - 1920
+		return 21.20 + 0.84493*t - 0.076100*t*t + 0.0020936*t*t*t
+	case year < 1961:
+		t := year - 1950
+		return 29.07 + 0.407*t - t*t/233 + t*t*t/2547
+	case year < 1986:
+		t := year - 1975
+		return 45.45 + 1.067*t - t*t/260 - t*t*t/718
+	case year < 2005:
+		t := year - 2000
+		return 63.86 + 0.3345*t - 0.060374*t*t + 0.0017275*t*t*t +
+			0.000651814*t*t*t*t + 0.00002373599*t*t*t*t*t
+	case year < 2050:
+		t := year - 2000
+		return 62.92 + 0.32217*t + 0.005589*t*t
+	case year < 2150:
+		u := (year - 1820) / 100
+		return -20 + 32*u*u - 0.5628*(2150-year)
+	default:
+		u := (year - 1820) / 100
+		return -20 + 32*u*u
+	}
+}
+
+// ToDynamicalTime 将世界时儒略日换算为力学时儒略日（JDE）
+func ToDynamicalTime(jd float64) float64 {
+	return jd + DeltaT(yearOf(jd))/86400
+}
+
+// ToUniversalTime 将力学时儒略日（JDE）换算为世界时儒略日
+func ToUniversalTime(jde float64) float64 {
+	return jde - DeltaT(yearOf(jde))/86400
+}
+
+// yearOf 由儒略日求对应的小数年份
+func yearOf(jd float64) float64 {
+	return 2000 + (jd-J2000)/365.25
+}
+
+// normalizeDegrees 将角度规范到[0, 360)
+func normalizeDegrees(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	return deg
+}
+
+// sinDeg 以角度为参数的正弦
+func sinDeg(deg float64) float64 {
+	return math.Sin(deg * math.Pi / 180)
+}
+
+// cosDeg 以角度为参数的余弦
+func cosDeg(deg float64) float64 {
+	return math.Cos(deg * math.Pi / 180)
+}
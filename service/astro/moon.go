@@ -0,0 +1,188 @@
+package astro
+
+import "math"
+
+// synodicMonth 平均朔望月长度（日）
+const synodicMonth = 29.530588861
+
+// NewMoon 计算第k次朔（以2000年1月6日的朔为k=0）的时刻（世界时儒略日）。
+// 采用Meeus《天文算法》第49章的算法，误差在一分钟以内
+func NewMoon(k float64) float64 {
+	t := k / 1236.85
+	t2, t3, t4 := t*t, t*t*t, t*t*t*t
+
+	jde := 2451550.09766 + synodicMonth*k + 0.00015437*t2 - 0.000000150*t3 + 0.00000000073*t4
+	e := 1 - 0.002516*t - 0.0000074*t2
+	m := 2.5534 + 29.10535670*k - 0.0000014*t2 - 0.00000011*t3
+	mp := 201.5643 + 385.81693528*k + 0.0107582*t2 + 0.00001238*t3 - 0.000000058*t4
+	f := 160.7108 + 390.67050284*k - 0.0016118*t2 - 0.00000227*t3 + 0.000000011*t4
+	omega := 124.7746 - 1.56375588*k + 0.0020672*t2 + 0.00000215*t3
+
+	jde += -0.40720*sinDeg(mp) +
+		0.17241*e*sinDeg(m) +
+		0.01608*sinDeg(2*mp) +
+		0.01039*sinDeg(2*f) +
+		0.00739*e*sinDeg(mp-m) -
+		0.00514*e*sinDeg(mp+m) +
+		0.00208*e*e*sinDeg(2*m) -
+		0.00111*sinDeg(mp-2*f) -
+		0.00057*sinDeg(mp+2*f) +
+		0.00056*e*sinDeg(2*mp+m) -
+		0.00042*sinDeg(3*mp) +
+		0.00042*e*sinDeg(m+2*f) +
+		0.00038*e*sinDeg(m-2*f) -
+		0.00024*e*sinDeg(2*mp-m) -
+		0.00017*sinDeg(omega) -
+		0.00007*sinDeg(mp+2*m) +
+		0.00004*sinDeg(2*mp-2*f) +
+		0.00004*sinDeg(3*m) +
+		0.00003*sinDeg(mp+m-2*f) +
+		0.00003*sinDeg(2*mp+2*f) -
+		0.00003*sinDeg(mp+m+2*f) +
+		0.00003*sinDeg(mp-m+2*f) -
+		0.00002*sinDeg(mp-m-2*f) -
+		0.00002*sinDeg(3*mp+m) +
+		0.00002*sinDeg(4*mp)
+
+	// 行星摄动引起的附加改正
+	additional := [14][3]float64{
+		{299.77, 0.107408, 0.000325},
+		{251.88, 0.016321, 0.000165},
+		{251.83, 26.651886, 0.000164},
+		{349.42, 36.412478, 0.000126},
+		{84.66, 18.206239, 0.000110},
+		{141.74, 53.303771, 0.000062},
+		{207.14, 2.453732, 0.000060},
+		{154.84, 7.306860, 0.000056},
+		{34.52, 27.261239, 0.000047},
+		{207.19, 0.121824, 0.000042},
+		{291.34, 1.844379, 0.000040},
+		{161.72, 24.198154, 0.000037},
+		{239.56, 25.513099, 0.000035},
+		{331.55, 3.592518, 0.000023},
+	}
+	for i, a := range additional {
+		angle := a[0] + a[1]*k
+		if i == 0 {
+			angle -= 0.009173 * t2
+		}
+		jde += a[2] * sinDeg(angle)
+	}
+
+	return ToUniversalTime(jde)
+}
+
+// NewMoonIndex 求不晚于给定时刻（世界时儒略日）附近的朔的序号k的估计值
+func NewMoonIndex(jd float64) float64 {
+	return math.Floor((jd - 2451550.09766) / synodicMonth)
+}
+
+// moonTerm 月球周期项：D、M、M'、F的系数及黄经（1e-6度）、距离（1e-3千米）振幅
+type moonTerm struct {
+	D, M, Mp, F float64
+	L, R        float64
+}
+
+// moonLonTerms 月球黄经与距离周期项（Meeus《天文算法》表47.A，源自ELP-2000/82）
+var moonLonTerms = []moonTerm{
+	{0, 0, 1, 0, 6288774, -20905355}, {2, 0, -1, 0, 1274027, -3699111},
+	{2, 0, 0, 0, 658314, -2955968}, {0, 0, 2, 0, 213618, -569925},
+	{0, 1, 0, 0, -185116, 48888}, {0, 0, 0, 2, -114332, -3149},
+	{2, 0, -2, 0, 58793, 246158}, {2, -1, -1, 0, 57066, -152138},
+	{2, 0, 1, 0, 53322, -170733}, {2, -1, 0, 0, 45758, -204586},
+	{0, 1, -1, 0, -40923, -129620}, {1, 0, 0, 0, -34720, 108743},
+	{0, 1, 1, 0, -30383, 104755}, {2, 0, 0, -2, 15327, 10321},
+	{0, 0, 1, 2, -12528, 0}, {0, 0, 1, -2, 10980, 79661},
+	{4, 0, -1, 0, 10675, -34782}, {0, 0, 3, 0, 10034, -23210},
+	{4, 0, -2, 0, 8548, -21636}, {2, 1, -1, 0, -7888, 24208},
+	{2, 1, 0, 0, -6766, 30824}, {1, 0, -1, 0, -5163, -8379},
+	{1, 1, 0, 0, 4987, -16675}, {2, -1, 1, 0, 4036, -12831},
+	{2, 0, 2, 0, 3994, -10445}, {4, 0, 0, 0, 3861, -11650},
+	{2, 0, -3, 0, 3665, 14403}, {0, 1, -2, 0, -2689, -7003},
+	{2, 0, -1, 2, -2602, 0}, {2, -1, -2, 0, 2390, 10056},
+	{1, 0, 1, 0, -2348, 6322}, {2, -2, 0, 0, 2236, -9884},
+	{0, 1, 2, 0, -2120, 5751}, {0, 2, 0, 0, -2069, 0},
+	{2, -2, -1, 0, 2048, -4950}, {2, 0, 1, -2, -1773, 4130},
+	{2, 0, 0, 2, -1595, 0}, {4, -1, -1, 0, 1215, -3958},
+	{0, 0, 2, 2, -1110, 0}, {3, 0, -1, 0, -892, 3258},
+	{2, 1, 1, 0, -810, 2616}, {4, -1, -2, 0, 759, -1897},
+	{0, 2, -1, 0, -713, -2117}, {2, 2, -1, 0, -700, 2354},
+	{2, 1, -2, 0, 691, 0}, {2, -1, 0, -2, 596, 0},
+	{4, 0, 1, 0, 549, -1423}, {0, 0, 4, 0, 537, -1117},
+	{4, -1, 0, 0, 520, -1571}, {1, 0, -2, 0, -487, -1739},
+	{2, 1, 0, -2, -399, 0}, {0, 0, 2, -2, -381, -4421},
+	{1, 1, 1, 0, 351, 0}, {3, 0, -2, 0, -340, 0},
+	{4, 0, -3, 0, 330, 0}, {2, -1, 2, 0, 327, 0},
+	{0, 2, 1, 0, -323, 1165}, {1, 1, -1, 0, 299, 0},
+	{2, 0, 3, 0, 294, 0}, {2, 0, -1, -2, 0, 8752},
+}
+
+// moonLatTerms 月球黄纬周期项（表47.B中振幅不小于800的各项），L为振幅（1e-6度）
+var moonLatTerms = []moonTerm{
+	{0, 0, 0, 1, 5128122, 0}, {0, 0, 1, 1, 280602, 0},
+	{0, 0, 1, -1, 277693, 0}, {2, 0, 0, -1, 173237, 0},
+	{2, 0, -1, 1, 55413, 0}, {2, 0, -1, -1, 46271, 0},
+	{2, 0, 0, 1, 32573, 0}, {0, 0, 2, 1, 17198, 0},
+	{2, 0, 1, -1, 9266, 0}, {0, 0, 2, -1, 8822, 0},
+	{2, -1, 0, -1, 8216, 0}, {2, 0, -2, -1, 4324, 0},
+	{2, 0, 1, 1, 4200, 0}, {2, 1, 0, -1, -3359, 0},
+	{2, -1, -1, 1, 2463, 0}, {2, -1, 0, 1, 2211, 0},
+	{2, -1, -1, -1, 2065, 0}, {0, 1, -1, -1, -1870, 0},
+	{4, 0, -1, -1, 1828, 0}, {0, 1, 0, 1, -1794, 0},
+	{0, 0, 0, 3, -1749, 0}, {0, 1, -1, 1, -1565, 0},
+	{1, 0, 0, 1, -1491, 0}, {0, 1, 1, 1, -1475, 0},
+	{0, 1, 1, -1, -1410, 0}, {0, 1, 0, -1, -1344, 0},
+	{1, 0, 0, -1, -1335, 0}, {0, 0, 3, 1, 1107, 0},
+	{4, 0, 0, -1, 1021, 0}, {4, 0, -1, 1, 833, 0},
+}
+
+// MoonPosition 计算月球的视黄经、黄纬（度，黄道与春分点均为当日）及地月距离（千米）。
+// 采用Meeus《天文算法》第47章的截断级数，黄经误差约10角秒
+func MoonPosition(jde float64) (lon, lat, distance float64) {
+	t := (jde - J2000) / 36525
+	t2, t3, t4 := t*t, t*t*t, t*t*t*t
+
+	// 月球平黄经、平距角、太阳平近点角、月球平近点角、月球升交角距
+	lp := 218.3164477 + 481267.88123421*t - 0.0015786*t2 + t3/538841 - t4/65194000
+	d := 297.8501921 + 445267.1114034*t - 0.0018819*t2 + t3/545868 - t4/113065000
+	m := 357.5291092 + 35999.0502909*t - 0.0001536*t2 + t3/24490000
+	mp := 134.9633964 + 477198.8675055*t + 0.0087414*t2 + t3/69699 - t4/14712000
+	f := 93.2720950 + 483202.0175233*t - 0.0036539*t2 - t3/3526000 + t4/863310000
+	a1 := 119.75 + 131.849*t
+	a2 := 53.09 + 479264.290*t
+	a3 := 313.45 + 481266.484*t
+	// 地球轨道偏心率减小引起的含M项的振幅修正
+	e := 1 - 0.002516*t - 0.0000074*t2
+	eccentricity := func(term moonTerm) float64 {
+		switch math.Abs(term.M) {
+		case 1:
+			return e
+		case 2:
+			return e * e
+		default:
+			return 1
+		}
+	}
+
+	var sumL, sumR, sumB float64
+	for _, term := range moonLonTerms {
+		arg := term.D*d + term.M*m + term.Mp*mp + term.F*f
+		factor := eccentricity(term)
+		sumL += term.L * factor * sinDeg(arg)
+		sumR += term.R * factor * cosDeg(arg)
+	}
+	for _, term := range moonLatTerms {
+		arg := term.D*d + term.M*m + term.Mp*mp + term.F*f
+		sumB += term.L * eccentricity(term) * sinDeg(arg)
+	}
+	// 金星、木星摄动及地球扁率引起的附加项
+	sumL += 3958*sinDeg(a1) + 1962*sinDeg(lp-f) + 318*sinDeg(a2)
+	sumB += -2235*sinDeg(lp) + 382*sinDeg(a3) + 175*sinDeg(a1-f) + 175*sinDeg(a1+f) +
+		127*sinDeg(lp-mp) - 115*sinDeg(lp+mp)
+
+	deltaPsi, _ := Nutation(jde)
+	lon = normalizeDegrees(lp + sumL/1e6 + deltaPsi/3600)
+	lat = sumB / 1e6
+	distance = 385000.56 + sumR/1000
+	return lon, lat, distance
+}
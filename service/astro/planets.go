@@ -0,0 +1,128 @@
+package astro
+
+import "math"
+
+// Planet 行星（含冥王星）
+type Planet int
+
+const (
+	Mercury Planet = iota
+	Venus
+	Mars
+	Jupiter
+	Saturn
+	Uranus
+	Neptune
+	Pluto
+)
+
+// keplerElements 行星轨道根数及其每儒略世纪的变化率（J2000黄道与春分点）：
+// 半长径a（天文单位）、偏心率e、倾角I、平黄经L、近日点黄经ϖ、升交点黄经Ω（度）
+type keplerElements struct {
+	A, E, I, L, Peri, Node                         float64
+	ARate, ERate, IRate, LRate, PeriRate, NodeRate float64
+}
+
+// PlanetMinYear、PlanetMaxYear 行星轨道根数的适用年份范围，超出后误差迅速增大
+const (
+	PlanetMinYear = 1800
+	PlanetMaxYear = 2050
+)
+
+// planetElements 采用JPL《Approximate Positions of the Planets》表1，适用于1800至2050年，
+// 黄经误差水星至火星及天王星以外在1角分以内，木星、土星约10角分
+var planetElements = map[Planet]keplerElements{
+	Mercury: {0.38709927, 0.20563593, 7.00497902, 252.25032350, 77.45779628, 48.33076593,
+		0.00000037, 0.00001906, -0.00594749, 149472.67411175, 0.16047689, -0.12534081},
+	Venus: {0.72333566, 0.00677672, 3.39467605, 181.97909950, 131.60246718, 76.67984255,
+		0.00000390, -0.00004107, -0.00078890, 58517.81538729, 0.00268329, -0.27769418},
+	Mars: {1.52371034, 0.09339410, 1.84969142, -4.55343205, -23.94362959, 49.55953891,
+		0.00001847, 0.00007882, -0.00813131, 19140.30268499, 0.44441088, -0.29257343},
+	Jupiter: {5.20288700, 0.04838624, 1.30439695, 34.39644051, 14.72847983, 100.47390909,
+		-0.00011607, -0.00013253, -0.00183714, 3034.74612775, 0.21252668, 0.20469106},
+	Saturn: {9.53667594, 0.05386179, 2.48599187, 49.95424423, 92.59887831, 113.66242448,
+		-0.00125060, -0.00050991, 0.00193609, 1222.49362201, -0.41897216, -0.28867794},
+	Uranus: {19.18916464, 0.04725744, 0.77263783, 313.23810451, 170.95427630, 74.01692503,
+		-0.00196176, -0.00004397, -0.00242939, 428.48202785, 0.40805281, 0.04240589},
+	Neptune: {30.06992276, 0.00859048, 1.77004347, -55.12002969, 44.96476227, 131.78422574,
+		0.00026291, 0.00005105, 0.00035372, 218.45945325, -0.32241464, -0.00508664},
+	Pluto: {39.48211675, 0.24882730, 17.14001206, 238.92903833, 224.06891629, 110.30393684,
+		-0.00031596, 0.00005170, 0.00004818, 145.20780515, -0.04062942, -0.01183482},
+}
+
+// lightTimePerAU 光行一天文单位所需的时间（日）
+const lightTimePerAU = 0.0057755183
+
+// aberrationConstant 周年光行差常数（角秒）
+const aberrationConstant = 20.49552
+
+// precession 自J2000.0起的黄经总岁差（度），t为儒略世纪数
+func precession(t float64) float64 {
+	return (5029.0966*t + 1.11113*t*t) / 3600
+}
+
+// heliocentric 由轨道根数求行星的日心黄道直角坐标（天文单位，J2000黄道与春分点）
+func (p Planet) heliocentric(jde float64) (x, y, z float64) {
+	el := planetElements[p]
+	t := (jde - J2000) / 36525
+	a := el.A + el.ARate*t
+	e := el.E + el.ERate*t
+	incl := el.I + el.IRate*t
+	meanLon := el.L + el.LRate*t
+	peri := el.Peri + el.PeriRate*t
+	node := el.Node + el.NodeRate*t
+
+	// 解开普勒方程求偏近点角
+	m := normalizeDegrees(meanLon-peri) * math.Pi / 180
+	ecc := m + e*math.Sin(m)
+	for i := 0; i < 30; i++ {
+		delta := (ecc - e*math.Sin(ecc) - m) / (1 - e*math.Cos(ecc))
+		ecc -= delta
+		if math.Abs(delta) < 1e-12 {
+			break
+		}
+	}
+
+	// 轨道平面内的坐标，再经近日点角距、倾角与升交点旋转到黄道坐标
+	xp := a * (math.Cos(ecc) - e)
+	yp := a * math.Sqrt(1-e*e) * math.Sin(ecc)
+	omega := peri - node
+	cw, sw := cosDeg(omega), sinDeg(omega)
+	cn, sn := cosDeg(node), sinDeg(node)
+	ci, si := cosDeg(incl), sinDeg(incl)
+	x = (cw*cn-sw*sn*ci)*xp + (-sw*cn-cw*sn*ci)*yp
+	y = (cw*sn+sw*cn*ci)*xp + (-sw*sn+cw*cn*ci)*yp
+	z = sw*si*xp + cw*si*yp
+	return x, y, z
+}
+
+// earthJ2000 地球的日心黄道直角坐标（J2000黄道与春分点），由VSOP87级数求得后扣除岁差
+func earthJ2000(jde float64) (x, y, z float64) {
+	lon, lat, radius := EarthHeliocentric(jde)
+	lon -= precession((jde-J2000)/36525) * math.Pi / 180
+	return radius * math.Cos(lat) * math.Cos(lon), radius * math.Cos(lat) * math.Sin(lon), radius * math.Sin(lat)
+}
+
+// Position 计算行星的地心视黄经、黄纬（度，黄道与春分点均为当日）及地心距离（天文单位），
+// 已含光行时、岁差、章动与周年光行差改正
+func (p Planet) Position(jde float64) (lon, lat, distance float64) {
+	ex, ey, ez := earthJ2000(jde)
+	var dx, dy, dz float64
+	tau := 0.0
+	// 行星在光行时之前所处的位置，迭代两次即足够
+	for i := 0; i < 3; i++ {
+		px, py, pz := p.heliocentric(jde - tau)
+		dx, dy, dz = px-ex, py-ey, pz-ez
+		distance = math.Sqrt(dx*dx + dy*dy + dz*dz)
+		tau = lightTimePerAU * distance
+	}
+	lon = math.Atan2(dy, dx) * 180 / math.Pi
+	lat = math.Asin(dz/distance) * 180 / math.Pi
+
+	t := (jde - J2000) / 36525
+	deltaPsi, _ := Nutation(jde)
+	sun := SunApparentLongitude(jde)
+	aberration := -aberrationConstant * cosDeg(sun-lon) / cosDeg(lat)
+	lon = normalizeDegrees(lon + precession(t) + (deltaPsi+aberration)/3600)
+	return lon, lat, distance
+}
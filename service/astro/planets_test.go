@@ -0,0 +1,34 @@
+package astro
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMoonPosition(t *testing.T) {
+	// Meeus《天文算法》例47.a：1992年4月12日0时力学时
+	lon, lat, distance := MoonPosition(2448724.5)
+	if math.Abs(lon-133.167265) > 0.003 {
+		t.Errorf("视黄经得%.6f，应为133.167265", lon)
+	}
+	if math.Abs(lat-(-3.229126)) > 0.003 {
+		t.Errorf("黄纬得%.6f，应为-3.229126", lat)
+	}
+	if math.Abs(distance-368409.7) > 1 {
+		t.Errorf("地月距离得%.1f千米，应为368409.7", distance)
+	}
+}
+
+func TestPlanetPosition(t *testing.T) {
+	// Meeus《天文算法》例33.a：1992年12月20日0时力学时的金星
+	lon, lat, distance := Venus.Position(2448976.5)
+	if math.Abs(lon-313.08102) > 0.01 {
+		t.Errorf("视黄经得%.5f，应为313.08102", lon)
+	}
+	if math.Abs(lat-(-2.08474)) > 0.01 {
+		t.Errorf("黄纬得%.5f，应为-2.08474", lat)
+	}
+	if math.Abs(distance-0.910947) > 0.0005 {
+		t.Errorf("地心距离得%.6f，应为0.910947", distance)
+	}
+}
@@ -0,0 +1,61 @@
+package calendar
+
+import (
+	"fmt"
+	"time"
+)
+
+// DayInfo 公历某日的农历、干支与节气
+type DayInfo struct {
+	Solar       string    `json:"solar"`                // 公历日期，如2024-02-10
+	Weekday     string    `json:"weekday"`              // 星期
+	Lunar       LunarDate `json:"lunar"`                // 农历日期
+	LunarText   string    `json:"lunar_text"`           // 农历日期的中文写法，如甲辰年正月初一
+	Zodiac      string    `json:"zodiac"`               // 农历年的生肖
+	LeapMonth   int       `json:"leap_month"`           // 当年农历闰月月序，无闰月为0
+	MonthDays   int       `json:"month_days"`           // 所在农历月的天数
+	YearGanzhi  string    `json:"year_ganzhi"`          // 年干支（以立春为界）
+	MonthGanzhi string    `json:"month_ganzhi"`         // 月干支（以节为界）
+	DayGanzhi   string    `json:"day_ganzhi"`           // 日干支
+	SolarTerm   string    `json:"solar_term,omitempty"` // 当日所交节气
+	TermTime    string    `json:"term_time,omitempty"`  // 交节时刻（北京时间）
+}
+
+var weekdayNames = [7]string{"星期日", "星期一", "星期二", "星期三", "星期四", "星期五", "星期六"}
+
+// DayInfoOf 求公历某日的农历与干支。年、月干支以当日结束时为准，即交节当日已属新月
+func DayInfoOf(year int, month time.Month, day int) (*DayInfo, error) {
+	if year < MinLunarYear || year > MaxLunarYear {
+		return nil, fmt.Errorf("公历年份应在%d至%d之间", MinLunarYear, MaxLunarYear)
+	}
+	date := time.Date(year, month, day, 0, 0, 0, 0, ChinaZone)
+	if date.Day() != day || date.Month() != month {
+		return nil, fmt.Errorf("公历日期有误")
+	}
+
+	lunar := LunarOfDate(year, month, day)
+	end := date.Add(24*time.Hour - time.Second)
+	ys, yb := YearGanzhi(end)
+	ms, mb := MonthGanzhi(end)
+	ds, db := DayGanzhi(date)
+	info := &DayInfo{
+		Solar:       date.Format("2006-01-02"),
+		Weekday:     weekdayNames[date.Weekday()],
+		Lunar:       lunar,
+		LunarText:   lunar.String(),
+		Zodiac:      lunar.Zodiac(),
+		LeapMonth:   LeapMonth(lunar.Year),
+		MonthDays:   MonthDays(lunar.Year, lunar.Month, lunar.IsLeap),
+		YearGanzhi:  string(ys) + string(yb),
+		MonthGanzhi: string(ms) + string(mb),
+		DayGanzhi:   string(ds) + string(db),
+	}
+	for _, term := range SolarTermsOfYear(year) {
+		if term.Time.Format("2006-01-02") == info.Solar {
+			info.SolarTerm = term.Name
+			info.TermTime = term.Time.Format("2006-01-02 15:04:05")
+			break
+		}
+	}
+	return info, nil
+}
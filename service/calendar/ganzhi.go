@@ -0,0 +1,39 @@
+package calendar
+
+import (
+	"time"
+
+	"github.com/hobbyqhd/yijing/service/models"
+)
+
+// SexagenaryDay 公历日期在六十甲子中的序数（甲子为0），以儒略日数连续推算，
+// 2000年1月1日为戊午日（序数54）
+func SexagenaryDay(year int, month time.Month, day int) int {
+	return mod(julianDayNumber(year, int(month), day)+49, 60)
+}
+
+// JulianDayNumber 公历日期的儒略日数，可用于推算二十八宿等按日循环的序列
+func JulianDayNumber(year int, month time.Month, day int) int {
+	return julianDayNumber(year, int(month), day)
+}
+
+// DayGanzhi 日干支，按北京时间取日期
+func DayGanzhi(t time.Time) (models.Stem, models.Branch) {
+	t = t.In(ChinaZone)
+	index := SexagenaryDay(t.Year(), t.Month(), t.Day())
+	return models.Stems[index%10], models.Branches[index%12]
+}
+
+// YearGanzhi 干支纪年，以立春为岁首
+func YearGanzhi(t time.Time) (models.Stem, models.Branch) {
+	year, _ := SolarMonth(t)
+	return models.Stems[mod(year-4, 10)], models.Branches[mod(year-4, 12)]
+}
+
+// MonthGanzhi 干支纪月，以节为月首，月干按五虎遁由年干推出
+func MonthGanzhi(t time.Time) (models.Stem, models.Branch) {
+	year, month := SolarMonth(t)
+	// 甲己之年丙作首：寅月天干自年干序数的两倍加二起
+	first := mod(year-4, 10)%5*2 + 2
+	return models.Stems[(first+month-1)%10], models.Branches[(month+1)%12]
+}
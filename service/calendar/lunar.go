@@ -0,0 +1,284 @@
+package calendar
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/hobbyqhd/yijing/service/astro"
+	"github.com/hobbyqhd/yijing/service/models"
+)
+
+// ChinaZone 农历以东经120度标准时（北京时间）为准
+var ChinaZone = time.FixedZone("CST", 8*3600)
+
+// 支持换算的农历年份范围
+const (
+	MinLunarYear = 1900
+	MaxLunarYear = 2100
+)
+
+// LunarDate 农历日期
+type LunarDate struct {
+	Year   int  `json:"year"`    // 农历年，以正月初一为岁首，用公历年数表示
+	Month  int  `json:"month"`   // 月（1-12）
+	Day    int  `json:"day"`     // 日（1-30）
+	IsLeap bool `json:"is_leap"` // 是否为闰月
+}
+
+// YearStem 农历年的天干
+func (d LunarDate) YearStem() models.Stem {
+	return models.Stems[mod(d.Year-4, 10)]
+}
+
+// YearBranch 农历年的地支
+func (d LunarDate) YearBranch() models.Branch {
+	return models.Branches[mod(d.Year-4, 12)]
+}
+
+// Zodiac 农历年的生肖
+func (d LunarDate) Zodiac() string {
+	return zodiacAnimals[mod(d.Year-4, 12)]
+}
+
+// String 以“甲辰年闰四月初五”的形式输出农历日期
+func (d LunarDate) String() string {
+	leap := ""
+	if d.IsLeap {
+		leap = "闰"
+	}
+	return fmt.Sprintf("%s%s年%s%s月%s", d.YearStem(), d.YearBranch(), leap, monthNames[d.Month-1], dayName(d.Day))
+}
+
+// zodiacAnimals 十二生肖，与十二地支依次相配
+var zodiacAnimals = [12]string{"鼠", "牛", "虎", "兔", "龙", "蛇", "马", "羊", "猴", "鸡", "狗", "猪"}
+
+var monthNames = [12]string{"正", "二", "三", "四", "五", "六", "七", "八", "九", "十", "冬", "腊"}
+
+var numerals = [11]string{"", "一", "二", "三", "四", "五", "六", "七", "八", "九", "十"}
+
+// dayName 农历日的中文名称
+func dayName(day int) string {
+	switch {
+	case day <= 10:
+		return "初" + numerals[day]
+	case day < 20:
+		return "十" + numerals[day-10]
+	case day == 20:
+		return "二十"
+	case day < 30:
+		return "廿" + numerals[day-20]
+	default:
+		return "三十"
+	}
+}
+
+// SolarToLunar 将给定时刻换算为农历日期，按北京时间确定所在日
+func SolarToLunar(t time.Time) LunarDate {
+	t = t.In(ChinaZone)
+	return LunarOfDate(t.Year(), t.Month(), t.Day())
+}
+
+// LunarOfDate 公历日期对应的农历日期，不考虑时区，适用于已知当地日期的场合
+func LunarOfDate(year int, month time.Month, day int) LunarDate {
+	jdn := julianDayNumber(year, int(month), day)
+	for _, y := range []int{year, year + 1} {
+		for _, m := range monthsOfSui(y) {
+			if jdn >= m.start && jdn < m.start+m.days {
+				return LunarDate{Year: m.year, Month: m.number, Day: jdn - m.start + 1, IsLeap: m.leap}
+			}
+		}
+	}
+	// 前后两个岁实已覆盖整个公历年，正常情况下不会到达此处
+	return LunarDate{}
+}
+
+// LunarToSolar 将农历日期换算为公历日期（北京时间零时）
+func LunarToSolar(d LunarDate) (time.Time, error) {
+	if d.Year < MinLunarYear || d.Year > MaxLunarYear {
+		return time.Time{}, fmt.Errorf("农历年份应在%d至%d之间", MinLunarYear, MaxLunarYear)
+	}
+	if d.Month < 1 || d.Month > 12 || d.Day < 1 || d.Day > 30 {
+		return time.Time{}, fmt.Errorf("农历日期有误")
+	}
+	m, ok := findLunarMonth(d.Year, d.Month, d.IsLeap)
+	if !ok {
+		return time.Time{}, fmt.Errorf("农历%d年没有闰%s月", d.Year, monthNames[d.Month-1])
+	}
+	if d.Day > m.days {
+		leap := ""
+		if d.IsLeap {
+			leap = "闰"
+		}
+		return time.Time{}, fmt.Errorf("农历%d年%s%s月只有%d天", d.Year, leap, monthNames[d.Month-1], m.days)
+	}
+	year, month, day := fromJulianDayNumber(m.start + d.Day - 1)
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, ChinaZone), nil
+}
+
+// LeapMonth 农历某年的闰月月序，无闰月时为0
+func LeapMonth(year int) int {
+	for number := 1; number <= 12; number++ {
+		if _, ok := findLunarMonth(year, number, true); ok {
+			return number
+		}
+	}
+	return 0
+}
+
+// MonthDays 农历某月的天数（29或30），该月不存在时为0
+func MonthDays(year, month int, leap bool) int {
+	m, ok := findLunarMonth(year, month, leap)
+	if !ok {
+		return 0
+	}
+	return m.days
+}
+
+// findLunarMonth 查找农历某年的某月。正月至十月在y年的岁实中，冬月、腊月可能跨入y+1年的岁实
+func findLunarMonth(year, number int, leap bool) (lunarMonth, bool) {
+	for _, y := range []int{year, year + 1} {
+		for _, m := range monthsOfSui(y) {
+			if m.year == year && m.number == number && m.leap == leap {
+				return m, true
+			}
+		}
+	}
+	return lunarMonth{}, false
+}
+
+// lunarMonth 农历月
+type lunarMonth struct {
+	year   int  // 所属农历年
+	number int  // 月序（1-12）
+	leap   bool // 是否闰月
+	start  int  // 初一的儒略日数（北京时间）
+	days   int  // 本月天数
+}
+
+var (
+	suiCache   = make(map[int][]lunarMonth)
+	suiCacheMu sync.Mutex
+)
+
+// monthsOfSui 推算一个岁实内的各月：自含y-1年冬至的十一月起，至含y年冬至的十一月之前。
+// 岁中有十三个月时置闰，以其中第一个不含中气的月为闰月
+func monthsOfSui(y int) []lunarMonth {
+	suiCacheMu.Lock()
+	defer suiCacheMu.Unlock()
+	if months, ok := suiCache[y]; ok {
+		return months
+	}
+
+	// 两个冬至及其间的中气（冬至、大寒、雨水……小雪、冬至）
+	solstice := astro.SunLongitudeTime(270, astro.JulianDay(time.Date(y-1, 12, 21, 0, 0, 0, 0, time.UTC)))
+	principal := make([]int, 13)
+	for i := range principal {
+		jd := solstice
+		if i > 0 {
+			jd = astro.SunLongitudeTime(math.Mod(270+30*float64(i), 360), solstice+30.44*float64(i))
+		}
+		principal[i] = dayNumber(jd)
+	}
+
+	// 朔日：取冬至之前的朔起算，直至覆盖下一个冬至
+	k := astro.NewMoonIndex(solstice) - 1
+	newMoons := make([]int, 0, 16)
+	for len(newMoons) < 3 || newMoons[len(newMoons)-2] <= principal[12] {
+		newMoons = append(newMoons, dayNumber(astro.NewMoon(k)))
+		k++
+	}
+
+	// 定位包含两个冬至的月
+	first, last := 0, 0
+	for i := 0; i+1 < len(newMoons); i++ {
+		if newMoons[i] <= principal[0] && principal[0] < newMoons[i+1] {
+			first = i
+		}
+		if newMoons[i] <= principal[12] && principal[12] < newMoons[i+1] {
+			last = i
+		}
+	}
+
+	leapIndex := -1
+	if last-first == 13 {
+		for i := first + 1; i < last; i++ {
+			if !containsPrincipal(newMoons[i], newMoons[i+1], principal) {
+				leapIndex = i
+				break
+			}
+		}
+	}
+
+	months := make([]lunarMonth, 0, last-first)
+	number, year := 11, y-1
+	for i := first; i < last; i++ {
+		leap := i == leapIndex
+		if i > first && !leap {
+			number = number%12 + 1
+			if number == 1 {
+				year = y
+			}
+		}
+		months = append(months, lunarMonth{
+			year:   year,
+			number: number,
+			leap:   leap,
+			start:  newMoons[i],
+			days:   newMoons[i+1] - newMoons[i],
+		})
+	}
+
+	suiCache[y] = months
+	return months
+}
+
+// containsPrincipal 判断[start, end)之间是否含有中气
+func containsPrincipal(start, end int, principal []int) bool {
+	for _, day := range principal {
+		if day >= start && day < end {
+			return true
+		}
+	}
+	return false
+}
+
+// standardTimeJD 1929年1月1日起采用东经120度标准时，此前历书按北京地方平时（东经116度25分）推算
+const standardTimeJD = 2425612.5
+
+// dayNumber 世界时儒略日所在的北京时间日期对应的儒略日数
+func dayNumber(jd float64) int {
+	offset := 8.0 / 24
+	if jd < standardTimeJD {
+		offset = (116 + 25.0/60) / 360
+	}
+	return int(math.Floor(jd + 0.5 + offset))
+}
+
+// julianDayNumber 公历日期对应的儒略日数
+func julianDayNumber(year, month, day int) int {
+	a := (14 - month) / 12
+	y := year + 4800 - a
+	m := month + 12*a - 3
+	return day + (153*m+2)/5 + 365*y + y/4 - y/100 + y/400 - 32045
+}
+
+// fromJulianDayNumber 儒略日数对应的公历日期
+func fromJulianDayNumber(jdn int) (year, month, day int) {
+	a := jdn + 32044
+	b := (4*a + 3) / 146097
+	c := a - 146097*b/4
+	d := (4*c + 3) / 1461
+	e := c - 1461*d/4
+	m := (5*e + 2) / 153
+	day = e - (153*m+2)/5 + 1
+	month = m + 3 - 12*(m/10)
+	year = 100*b + d - 4800 + m/10
+	return
+}
+
+// mod 取非负余数
+func mod(a, n int) int {
+	return (a%n + n) % n
+}
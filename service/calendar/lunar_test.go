@@ -0,0 +1,81 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLunarOfDate(t *testing.T) {
+	tests := []struct {
+		year  int
+		month time.Month
+		day   int
+		want  string
+	}{
+		{2024, 2, 10, "甲辰年正月初一"},
+		{2023, 3, 22, "癸卯年闰二月初一"},
+		{2020, 5, 23, "庚子年闰四月初一"},
+		{2033, 11, 22, "癸丑年冬月初一"},
+		{2033, 12, 22, "癸丑年闰冬月初一"},
+		{2034, 1, 20, "癸丑年腊月初一"},
+		// 2057年九月朔在北京时间9月28日23时59分余，距零时仅数秒，有历表排作9月29日
+		{2057, 9, 27, "丁丑年八月廿九"},
+		{2057, 9, 28, "丁丑年九月初一"},
+		// 1929年前按北京地方平时（东经116度25分）定朔，以下两个朔在东经120度标准时刚过零时，地方时尚在前一日
+		{1914, 11, 16, "甲寅年九月廿九"},
+		{1914, 11, 17, "甲寅年十月初一"},
+		{1920, 11, 9, "庚申年九月廿九"},
+		{1920, 11, 10, "庚申年十月初一"},
+	}
+	for _, tt := range tests {
+		if got := LunarOfDate(tt.year, tt.month, tt.day).String(); got != tt.want {
+			t.Errorf("%d-%02d-%02d: 得%s，应为%s", tt.year, tt.month, tt.day, got, tt.want)
+		}
+	}
+}
+
+func TestLeapMonth(t *testing.T) {
+	tests := []struct {
+		year, want int
+	}{
+		{2020, 4},
+		{2023, 2},
+		{2024, 0},
+		{2033, 11},
+	}
+	for _, tt := range tests {
+		if got := LeapMonth(tt.year); got != tt.want {
+			t.Errorf("%d年闰月: 得%d，应为%d", tt.year, got, tt.want)
+		}
+	}
+}
+
+func TestLunarToSolar(t *testing.T) {
+	tests := []struct {
+		lunar LunarDate
+		want  string
+	}{
+		{LunarDate{Year: 2024, Month: 1, Day: 1}, "2024-02-10"},
+		{LunarDate{Year: 2023, Month: 2, Day: 1, IsLeap: true}, "2023-03-22"},
+		{LunarDate{Year: 2020, Month: 4, Day: 1, IsLeap: true}, "2020-05-23"},
+		{LunarDate{Year: 2033, Month: 11, Day: 1, IsLeap: true}, "2033-12-22"},
+		{LunarDate{Year: 2057, Month: 9, Day: 1}, "2057-09-28"},
+		{LunarDate{Year: 1914, Month: 10, Day: 1}, "1914-11-17"},
+		{LunarDate{Year: 1920, Month: 10, Day: 1}, "1920-11-10"},
+	}
+	for _, tt := range tests {
+		got, err := LunarToSolar(tt.lunar)
+		if err != nil {
+			t.Errorf("%s: %v", tt.lunar, err)
+			continue
+		}
+		if got.Format("2006-01-02") != tt.want {
+			t.Errorf("%s: 得%s，应为%s", tt.lunar, got.Format("2006-01-02"), tt.want)
+		}
+	}
+
+	// 2024年无闰月
+	if _, err := LunarToSolar(LunarDate{Year: 2024, Month: 2, Day: 1, IsLeap: true}); err == nil {
+		t.Error("2024年闰二月应当报错")
+	}
+}
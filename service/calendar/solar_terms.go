@@ -0,0 +1,86 @@
+package calendar
+
+import (
+	"math"
+	"time"
+
+	"github.com/hobbyqhd/yijing/service/astro"
+)
+
+// SolarTermNames 二十四节气，自春分（太阳视黄经0度）起每15度一气
+var SolarTermNames = [24]string{
+	"春分", "清明", "谷雨", "立夏", "小满", "芒种",
+	"夏至", "小暑", "大暑", "立秋", "处暑", "白露",
+	"秋分", "寒露", "霜降", "立冬", "小雪", "大雪",
+	"冬至", "小寒", "大寒", "立春", "雨水", "惊蛰",
+}
+
+// SolarTerm 节气及其交节时刻
+type SolarTerm struct {
+	Name      string    `json:"name"`      // 节气名
+	Longitude int       `json:"longitude"` // 太阳视黄经（度）
+	Time      time.Time `json:"time"`      // 交节时刻（北京时间）
+	IsJie     bool      `json:"is_jie"`    // 是否为“节”（立春、惊蛰等月首之气），否则为中气
+}
+
+// SolarTermTime 求公历某年中太阳视黄经到达指定度数（15的倍数）的时刻，精确到秒
+func SolarTermTime(year, longitude int) time.Time {
+	// 太阳在1月1日前后位于黄经280度附近，据此估算日期后迭代求精
+	days := math.Mod(float64(longitude-280+360), 360) / 360 * 365.2422
+	estimate := astro.JulianDay(time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)) + days
+	return astro.TimeFromJulianDay(astro.SunLongitudeTime(float64(longitude), estimate)).In(ChinaZone)
+}
+
+// SolarTermsOfYear 公历某年的二十四节气，自小寒起按时间排列
+func SolarTermsOfYear(year int) []SolarTerm {
+	terms := make([]SolarTerm, 0, 24)
+	for i := 0; i < 24; i++ {
+		index := (19 + i) % 24
+		longitude := index * 15
+		terms = append(terms, SolarTerm{
+			Name:      SolarTermNames[index],
+			Longitude: longitude,
+			Time:      SolarTermTime(year, longitude),
+			IsJie:     index%2 == 1,
+		})
+	}
+	return terms
+}
+
+// SolarMonth 节气月：以立春为岁首，每交一“节”进一月。
+// 返回所属的干支纪年（以公历年数表示）与月序（寅月为1，丑月为12）
+func SolarMonth(t time.Time) (year, month int) {
+	longitude := astro.SunApparentLongitude(astro.ToDynamicalTime(astro.JulianDay(t)))
+	month = int(math.Mod(longitude-315+360, 360)/30) + 1
+
+	t = t.In(ChinaZone)
+	year = t.Year()
+	// 立春前的子月、丑月仍属上一年
+	if month >= 11 && t.Month() <= time.February {
+		year--
+	}
+	return year, month
+}
+
+// JieAround 求某一时刻前后相邻的两个“节”（立春、惊蛰……小寒），用于推算大运起运岁数
+func JieAround(t time.Time) (prev, next SolarTerm) {
+	jd := astro.JulianDay(t)
+	longitude := astro.SunApparentLongitude(astro.ToDynamicalTime(jd))
+	// 节位于黄经315度起每30度处
+	passed := math.Mod(longitude-315+360, 360)
+	prevLongitude := math.Mod(315+30*math.Floor(passed/30), 360)
+	prevJD := astro.SunLongitudeTime(prevLongitude, jd-(passed-30*math.Floor(passed/30))/0.9856)
+	nextJD := astro.SunLongitudeTime(math.Mod(prevLongitude+30, 360), prevJD+30.4)
+	return jieTerm(prevLongitude, prevJD), jieTerm(math.Mod(prevLongitude+30, 360), nextJD)
+}
+
+// jieTerm 由黄经与世界时儒略日构造节气
+func jieTerm(longitude, jd float64) SolarTerm {
+	index := int(math.Round(longitude/15)) % 24
+	return SolarTerm{
+		Name:      SolarTermNames[index],
+		Longitude: index * 15,
+		Time:      astro.TimeFromJulianDay(jd).In(ChinaZone),
+		IsJie:     true,
+	}
+}
@@ -0,0 +1,69 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hobbyqhd/yijing/service/models"
+)
+
+func TestSolarTermTime(t *testing.T) {
+	tests := []struct {
+		name      string
+		year      int
+		longitude int
+		want      time.Time
+	}{
+		{"2024年立春", 2024, 315, time.Date(2024, 2, 4, 16, 27, 5, 0, ChinaZone)},
+		{"2024年冬至", 2024, 270, time.Date(2024, 12, 21, 17, 20, 24, 0, ChinaZone)},
+	}
+	for _, tt := range tests {
+		got := SolarTermTime(tt.year, tt.longitude)
+		if diff := got.Sub(tt.want); diff < -5*time.Second || diff > 5*time.Second {
+			t.Errorf("%s: 得%v，应为%v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPillarsAroundLichun(t *testing.T) {
+	// 2024年立春交于2月4日16时27分
+	tests := []struct {
+		time                time.Time
+		yearStem, monthStem models.Stem
+		yearBranch          models.Branch
+		monthBranch         models.Branch
+	}{
+		{time.Date(2024, 2, 4, 16, 20, 0, 0, ChinaZone), "癸", "乙", "卯", "丑"},
+		{time.Date(2024, 2, 4, 16, 35, 0, 0, ChinaZone), "甲", "丙", "辰", "寅"},
+	}
+	for _, tt := range tests {
+		ys, yb := YearGanzhi(tt.time)
+		ms, mb := MonthGanzhi(tt.time)
+		if ys != tt.yearStem || yb != tt.yearBranch || ms != tt.monthStem || mb != tt.monthBranch {
+			t.Errorf("%v: 得%s%s年%s%s月，应为%s%s年%s%s月", tt.time, ys, yb, ms, mb,
+				tt.yearStem, tt.yearBranch, tt.monthStem, tt.monthBranch)
+		}
+	}
+}
+
+func TestSolarMonth(t *testing.T) {
+	// 2024年大雪交于12月6日，2025年小寒交于1月5日，其间为子月；2025年立春交于2月3日22时，此前仍属甲辰年
+	tests := []struct {
+		time        time.Time
+		year, month int
+	}{
+		{time.Date(2024, 12, 6, 12, 0, 0, 0, ChinaZone), 2024, 10},
+		{time.Date(2024, 12, 7, 12, 0, 0, 0, ChinaZone), 2024, 11},
+		{time.Date(2024, 12, 31, 12, 0, 0, 0, ChinaZone), 2024, 11},
+		{time.Date(2025, 1, 4, 12, 0, 0, 0, ChinaZone), 2024, 11},
+		{time.Date(2025, 1, 6, 12, 0, 0, 0, ChinaZone), 2024, 12},
+		{time.Date(2025, 2, 3, 12, 0, 0, 0, ChinaZone), 2024, 12},
+		{time.Date(2025, 2, 3, 23, 0, 0, 0, ChinaZone), 2025, 1},
+	}
+	for _, tt := range tests {
+		year, month := SolarMonth(tt.time)
+		if year != tt.year || month != tt.month {
+			t.Errorf("%v: 得%d年第%d月，应为%d年第%d月", tt.time, year, month, tt.year, tt.month)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package calendar
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/hobbyqhd/yijing/service/astro"
+)
+
+// ApparentSolarTime 将某一时刻换算为指定经度处的真太阳时：
+// 地方平太阳时为世界时加经度时差（每度四分钟），再加时差（equation of time）即为真太阳时。
+// 返回的时刻与t相同，但以真太阳时所在的固定时区表示；同时返回时差（分钟）
+func ApparentSolarTime(t time.Time, longitude float64) (time.Time, float64) {
+	eot := astro.EquationOfTime(astro.ToDynamicalTime(astro.JulianDay(t)))
+	offset := int(math.Round(longitude*240 + eot*60))
+	return t.In(time.FixedZone(fmt.Sprintf("LAT%+.2f", longitude), offset)), eot
+}
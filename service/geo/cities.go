@@ -0,0 +1,307 @@
+// Package geo 提供出生地等地点的经纬度与时区查询。地名数据内置于程序中，
+// 收录全国地级与县级行政区（含已改设为市辖区的原县、县级市）及世界主要城市，无需联网。
+// 其余市辖区按所属城市计
+package geo
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	_ "time/tzdata" // 内置IANA时区数据库，保证离线环境下也能按时区名换算
+	"unicode"
+)
+
+//go:embed cities.tsv
+var citiesTSV []byte
+
+// City 城市及其坐标、时区
+type City struct {
+	Name      string  `json:"name"`               // 中文名
+	Pinyin    string  `json:"pinyin"`             // 拼音，音节间以空格分隔
+	NameEn    string  `json:"nameEn"`             // 英文名
+	Province  string  `json:"province,omitempty"` // 省级行政区（仅限中国）
+	Country   string  `json:"country"`            // 国家或地区
+	Latitude  float64 `json:"latitude"`           // 纬度，北纬为正
+	Longitude float64 `json:"longitude"`          // 经度，东经为正
+	TimeZone  string  `json:"timeZone"`           // IANA时区名
+}
+
+// cityIndex 预先规范化的检索键
+type cityIndex struct {
+	pinyin   string // 全拼，如shijiazhuang
+	initials string // 拼音首字母，如sjz
+	english  string // 英文名，去除空格与标点后的小写形式
+}
+
+var (
+	cities  []City
+	indexes []cityIndex
+)
+
+func init() {
+	var err error
+	if cities, err = parseCities(citiesTSV); err != nil {
+		panic(err)
+	}
+	indexes = make([]cityIndex, len(cities))
+	for i, c := range cities {
+		syllables := strings.Fields(c.Pinyin)
+		initials := make([]byte, 0, len(syllables))
+		for _, s := range syllables {
+			initials = append(initials, s[0])
+		}
+		indexes[i] = cityIndex{
+			pinyin:   strings.Join(syllables, ""),
+			initials: string(initials),
+			english:  normalizeLatin(c.NameEn),
+		}
+	}
+}
+
+// parseCities 解析以制表符分隔的地名表，#开头的行为注释
+func parseCities(data []byte) ([]City, error) {
+	result := make([]City, 0, 3000)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, "\t")
+		if len(fields) != 8 {
+			return nil, fmt.Errorf("地名表第%d行应有8列，实有%d列", line, len(fields))
+		}
+		lat, err := strconv.ParseFloat(fields[5], 64)
+		if err != nil {
+			return nil, fmt.Errorf("地名表第%d行纬度有误: %v", line, err)
+		}
+		lon, err := strconv.ParseFloat(fields[6], 64)
+		if err != nil {
+			return nil, fmt.Errorf("地名表第%d行经度有误: %v", line, err)
+		}
+		result = append(result, City{
+			Name:      fields[0],
+			Pinyin:    fields[1],
+			NameEn:    fields[2],
+			Province:  fields[3],
+			Country:   fields[4],
+			Latitude:  lat,
+			Longitude: lon,
+			TimeZone:  fields[7],
+		})
+	}
+	return result, scanner.Err()
+}
+
+// ErrCityNotFound 地名表中没有该城市
+var ErrCityNotFound = errors.New("未找到城市")
+
+// Lookup 按中文名（可带“市”“县”等通名，可冠以省份或国家，如“安徽宿州”）、拼音或英文名精确查找城市。
+// 拼音或中文名对应多个城市时（如suzhou可指苏州或宿州）返回错误并列出候选，不擅自取其一
+func Lookup(name string) (*City, error) {
+	name = strings.TrimSpace(name)
+	latin := normalizeLatin(name)
+	var found []int
+	for i := range cities {
+		if matchChinese(cities[i], name) ||
+			(latin != "" && (indexes[i].pinyin == latin || indexes[i].english == latin)) {
+			found = append(found, i)
+		}
+	}
+	// 全称未中时再去掉“县”“旗”“自治县”及民族名重试，原样匹配优先，故“朝阳县”不会同时指朝阳市
+	for _, short := range shortForms(name) {
+		if len(found) > 0 {
+			break
+		}
+		for i := range cities {
+			if matchChinese(cities[i], short) {
+				found = append(found, i)
+			}
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, ErrCityNotFound
+	case 1:
+		return &cities[found[0]], nil
+	}
+	candidates := make([]string, len(found))
+	for k, i := range found {
+		candidates[k] = cities[i].qualifiedName()
+	}
+	return nil, fmt.Errorf("%s对应多个城市：%s，请冠以省份或国家，如“%s”", name, strings.Join(candidates, "、"), candidates[0])
+}
+
+// matchChinese 中文名是否指该城市，允许带“市”字，允许冠以省份（可带“省”字）或国家
+func matchChinese(c City, name string) bool {
+	for _, region := range []string{"", c.Province, c.Country} {
+		rest, ok := strings.CutPrefix(name, region)
+		if !ok || (region != "" && rest == name) {
+			continue
+		}
+		if region != "" {
+			rest = strings.TrimSpace(strings.TrimPrefix(rest, "省"))
+		}
+		if rest == c.Name || strings.TrimSuffix(rest, "市") == c.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// adminSuffixes 地名通名，较长者在前
+var adminSuffixes = []string{"自治州", "自治县", "自治旗", "县", "旗", "区"}
+
+// ethnicNames 自治地方名称中的民族名，如“长阳土家族自治县”中的“土家族”
+var ethnicNames = []string{
+	"蒙古族", "回族", "藏族", "维吾尔族", "苗族", "彝族", "壮族", "布依族", "朝鲜族", "满族",
+	"侗族", "瑶族", "白族", "土家族", "哈尼族", "哈萨克族", "傣族", "黎族", "傈僳族", "佤族",
+	"畲族", "高山族", "拉祜族", "水族", "东乡族", "纳西族", "景颇族", "柯尔克孜族", "土族", "达斡尔族",
+	"仫佬族", "羌族", "布朗族", "撒拉族", "毛南族", "仡佬族", "锡伯族", "阿昌族", "普米族", "塔吉克族",
+	"怒族", "乌孜别克族", "俄罗斯族", "鄂温克族", "德昂族", "保安族", "裕固族", "京族", "塔塔尔族", "独龙族",
+	"鄂伦春族", "赫哲族", "门巴族", "珞巴族", "基诺族", "各族",
+}
+
+// shortForms 依次去掉通名与民族名后的简称，如“长阳土家族自治县”得“长阳土家族”“长阳土家”“长阳”
+func shortForms(name string) []string {
+	var forms []string
+	for _, suffix := range adminSuffixes {
+		if rest, ok := strings.CutSuffix(name, suffix); ok && rest != "" {
+			name = rest
+			forms = append(forms, name)
+			break
+		}
+	}
+	for {
+		if rest, ok := strings.CutSuffix(name, "族"); ok && rest != "" {
+			forms = append(forms, rest) // 民族名即地名者，如“鄂温克族”得“鄂温克”
+		}
+		stripped := false
+		for _, ethnic := range ethnicNames {
+			if rest, ok := strings.CutSuffix(name, ethnic); ok && rest != "" {
+				name = rest
+				forms = append(forms, name)
+				stripped = true
+				break
+			}
+		}
+		if !stripped {
+			return forms
+		}
+	}
+}
+
+// qualifiedName 冠以省份（中国城市）或国家的地名，可直接交给Lookup查找
+func (c City) qualifiedName() string {
+	region := c.Province
+	if region == "" {
+		region = c.Country
+	}
+	if region == c.Name {
+		return c.Name
+	}
+	return region + c.Name
+}
+
+// Search 模糊检索城市：中文按地名或省份匹配，拉丁字母按全拼、拼音首字母或英文名匹配。
+// 结果按匹配程度排序，完全匹配优先于前缀匹配，前缀匹配优先于包含匹配
+func Search(query string, limit int) []City {
+	query = strings.TrimSpace(query)
+	if query == "" || limit <= 0 {
+		return []City{}
+	}
+
+	type match struct {
+		index int
+		score int
+	}
+	matches := make([]match, 0)
+	if isLatin(query) {
+		q := normalizeLatin(query)
+		if q == "" {
+			return []City{}
+		}
+		for i, idx := range indexes {
+			if score := latinScore(idx, q); score > 0 {
+				matches = append(matches, match{i, score})
+			}
+		}
+	} else {
+		q := strings.TrimSuffix(query, "市")
+		for i, c := range cities {
+			if score := chineseScore(c, q); score > 0 {
+				matches = append(matches, match{i, score})
+			}
+		}
+	}
+
+	sort.SliceStable(matches, func(a, b int) bool {
+		return matches[a].score > matches[b].score
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	result := make([]City, 0, len(matches))
+	for _, m := range matches {
+		result = append(result, cities[m.index])
+	}
+	return result
+}
+
+// chineseScore 中文查询的匹配得分
+func chineseScore(c City, q string) int {
+	switch {
+	case c.Name == q:
+		return 100
+	case strings.HasPrefix(c.Name, q):
+		return 80
+	case strings.Contains(c.Name, q):
+		return 60
+	case c.Province == q || c.Country == q:
+		return 30
+	}
+	return 0
+}
+
+// latinScore 拼音或英文查询的匹配得分
+func latinScore(idx cityIndex, q string) int {
+	switch {
+	case idx.pinyin == q || idx.english == q:
+		return 90
+	case strings.HasPrefix(idx.pinyin, q) || strings.HasPrefix(idx.english, q):
+		return 70
+	case len(q) >= 2 && idx.initials == q:
+		return 65
+	case len(q) >= 2 && strings.HasPrefix(idx.initials, q):
+		return 50
+	case len(q) >= 3 && (strings.Contains(idx.pinyin, q) || strings.Contains(idx.english, q)):
+		return 40
+	}
+	return 0
+}
+
+// isLatin 查询是否只含拉丁字母、数字与常见标点
+func isLatin(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeLatin 转为小写并去除空格、撇号、连字符等，使“Xi'an”“xi an”与“xian”等同
+func normalizeLatin(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if r >= 'a' && r <= 'z' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hobbyqhd/yijing/service/services"
+)
+
+type AlmanacHandler struct {
+	almanacService *services.AlmanacService
+}
+
+func NewAlmanacHandler() *AlmanacHandler {
+	return &AlmanacHandler{
+		almanacService: services.NewAlmanacService(),
+	}
+}
+
+// GetAlmanac 查询某日黄历，日期格式为2006-01-02
+func (h *AlmanacHandler) GetAlmanac(c *gin.Context) {
+	result, err := h.almanacService.GetAlmanac(c.Param("date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// SelectDates 择日：按所择之事与事主八字，在日期范围内挑选吉日吉时
+func (h *AlmanacHandler) SelectDates(c *gin.Context) {
+	var req services.DateSelectionInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求参数"})
+		return
+	}
+
+	result, err := h.almanacService.SelectDates(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hobbyqhd/yijing/service/services"
+)
+
+type AstrologyHandler struct {
+	astrologyService *services.AstrologyService
+}
+
+func NewAstrologyHandler() *AstrologyHandler {
+	return &AstrologyHandler{
+		astrologyService: services.NewAstrologyService(),
+	}
+}
+
+// Transits 行运报告：日期范围内行运星与本命星形成相位的起止与精确时刻
+func (h *AstrologyHandler) Transits(c *gin.Context) {
+	var req services.TransitInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求参数"})
+		return
+	}
+
+	result, err := h.astrologyService.Transits(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
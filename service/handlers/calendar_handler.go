@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hobbyqhd/yijing/service/calendar"
+)
+
+type CalendarHandler struct{}
+
+func NewCalendarHandler() *CalendarHandler {
+	return &CalendarHandler{}
+}
+
+type LunarRequest struct {
+	Date  string `form:"date" binding:"required"` // 日期，格式为2006-01-02
+	Lunar bool   `form:"lunar"`                   // date是否为农历日期
+	Leap  bool   `form:"leap"`                    // 农历日期是否在闰月
+}
+
+// GetLunar 公历与农历互查：默认按公历日期查询农历，lunar=true时按农历日期查询公历
+func (h *CalendarHandler) GetLunar(c *gin.Context) {
+	var req LunarRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请提供日期"})
+		return
+	}
+
+	var year, month, day int
+	if _, err := fmt.Sscanf(req.Date, "%d-%d-%d", &year, &month, &day); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "日期格式应为2006-01-02"})
+		return
+	}
+
+	if req.Lunar {
+		date, err := calendar.LunarToSolar(calendar.LunarDate{Year: year, Month: month, Day: day, IsLeap: req.Leap})
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		year, month, day = date.Year(), int(date.Month()), date.Day()
+	}
+
+	info, err := calendar.DayInfoOf(year, time.Month(month), day)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
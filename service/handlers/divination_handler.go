@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -37,13 +38,91 @@ func (h *DivinationHandler) CreateDivination(c *gin.Context) {
 	// 创建占卜记录
 	divination, err := h.divinationService.CreateDivination(userId, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(inputErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, divination)
+}
+
+// CompatibilityRequest 八字合婚请求，双方出生信息的格式与八字占卜的input相同
+type CompatibilityRequest struct {
+	Question string      `json:"question"`
+	First    interface{} `json:"first" binding:"required"`
+	Second   interface{} `json:"second" binding:"required"`
+}
+
+// CreateCompatibility 八字合婚
+func (h *DivinationHandler) CreateCompatibility(c *gin.Context) {
+	var req CompatibilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求参数"})
+		return
+	}
+	if req.Question == "" {
+		req.Question = "两人八字是否相合"
+	}
+
+	userId := c.GetUint("userId")
+
+	// 合婚结果与其他占卜一样保存为占卜记录
+	divination, err := h.divinationService.CreateDivination(userId, &services.DivinationRequest{
+		Type:     string(models.TypeCompatibility),
+		Question: req.Question,
+		Input:    services.CompatibilityInput{First: req.First, Second: req.Second},
+	})
+	if err != nil {
+		c.JSON(inputErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, divination)
+}
+
+// SynastryRequest 星盘合盘请求，双方出生信息的格式与星座占卜的input相同
+type SynastryRequest struct {
+	Question string      `json:"question"`
+	First    interface{} `json:"first" binding:"required"`
+	Second   interface{} `json:"second" binding:"required"`
+	services.AspectOptions
+}
+
+// CreateSynastry 星盘合盘
+func (h *DivinationHandler) CreateSynastry(c *gin.Context) {
+	var req SynastryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求参数"})
+		return
+	}
+	if req.Question == "" {
+		req.Question = "两人星盘是否相合"
+	}
+
+	userId := c.GetUint("userId")
+
+	// 合盘结果与其他占卜一样保存为占卜记录
+	divination, err := h.divinationService.CreateDivination(userId, &services.DivinationRequest{
+		Type:     string(models.TypeSynastry),
+		Question: req.Question,
+		Input:    services.SynastryInput{First: req.First, Second: req.Second, AspectOptions: req.AspectOptions},
+	})
+	if err != nil {
+		c.JSON(inputErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, divination)
 }
 
+// inputErrorStatus 请求参数有误时返回400，其余为500
+func inputErrorStatus(err error) int {
+	var inputErr *services.InputError
+	if errors.As(err, &inputErr) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
 func (h *DivinationHandler) GetUserDivinations(c *gin.Context) {
 	userId := c.GetUint("userId")
 
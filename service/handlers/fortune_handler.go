@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"time"
 
@@ -18,12 +20,24 @@ func NewFortuneHandler() *FortuneHandler {
 	}
 }
 
+// CalculateFortuneRequest 计算运势的可选参数
+type CalculateFortuneRequest struct {
+	Birth interface{} `json:"birth"` // 出生信息，格式与星座占卜的input相同，提供时按行运相位推算运势
+}
+
 func (h *FortuneHandler) CalculateFortune(c *gin.Context) {
+	// 请求体可以为空
+	var req CalculateFortuneRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求参数"})
+		return
+	}
+
 	userId := c.GetUint("userId")
 
-	fortune, err := h.fortuneService.CalculateFortune(userId)
+	fortune, err := h.fortuneService.CalculateFortune(userId, req.Birth)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(inputErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
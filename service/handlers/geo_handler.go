@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hobbyqhd/yijing/service/geo"
+)
+
+type GeoHandler struct{}
+
+func NewGeoHandler() *GeoHandler {
+	return &GeoHandler{}
+}
+
+type SearchCitiesRequest struct {
+	Query string `form:"q" binding:"required"`
+	Limit int    `form:"limit"`
+}
+
+// SearchCities 按中文名、拼音或英文名检索出生地
+func (h *GeoHandler) SearchCities(c *gin.Context) {
+	var req SearchCitiesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请提供检索关键字"})
+		return
+	}
+
+	// 默认返回10条，最多50条
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+	if req.Limit > 50 {
+		req.Limit = 50
+	}
+
+	c.JSON(http.StatusOK, geo.Search(req.Query, req.Limit))
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hobbyqhd/yijing/service/services"
+)
+
+type TarotHandler struct {
+	tarotService *services.TarotService
+}
+
+func NewTarotHandler() *TarotHandler {
+	return &TarotHandler{
+		tarotService: services.NewTarotService(),
+	}
+}
+
+// CreateDeck 上传自定义牌组
+func (h *TarotHandler) CreateDeck(c *gin.Context) {
+	var req services.TarotDeckInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求参数"})
+		return
+	}
+
+	deck, err := h.tarotService.CreateDeck(c.GetUint("userId"), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, deck)
+}
+
+// GetUserDecks 获取当前用户的自定义牌组
+func (h *TarotHandler) GetUserDecks(c *gin.Context) {
+	decks, err := h.tarotService.GetUserDecks(c.GetUint("userId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, decks)
+}
+
+// GetDeck 获取当前用户的某一牌组
+func (h *TarotHandler) GetDeck(c *gin.Context) {
+	deckId, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的牌组ID"})
+		return
+	}
+
+	deck, err := h.tarotService.GetDeck(c.GetUint("userId"), uint(deckId))
+	if err != nil {
+		c.JSON(deckErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, deck)
+}
+
+// DeleteDeck 删除当前用户的某一牌组
+func (h *TarotHandler) DeleteDeck(c *gin.Context) {
+	deckId, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的牌组ID"})
+		return
+	}
+
+	if err := h.tarotService.DeleteDeck(c.GetUint("userId"), uint(deckId)); err != nil {
+		c.JSON(deckErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// deckErrorStatus 牌组不存在时返回404，其余为500
+func deckErrorStatus(err error) int {
+	if errors.Is(err, services.ErrDeckNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
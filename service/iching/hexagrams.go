@@ -0,0 +1,139 @@
+// Package iching 收录六十四卦与八卦的经文资料（卦辞、大象、爻辞及用九、用六），
+// 并提供按卦序、卦象查找的方法
+package iching
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hobbyqhd/yijing/service/models"
+)
+
+//go:embed hexagrams.json
+var hexagramsJSON []byte
+
+// HexagramData 六十四卦中一卦的完整资料
+type HexagramData struct {
+	Number   int                  `json:"number"`              // 文王卦序（1-64）
+	Name     string               `json:"name"`                // 卦名
+	NameEn   string               `json:"name_en"`             // 英文卦名
+	Pattern  string               `json:"pattern"`             // 自初爻至上爻的阴阳，阳为1、阴为0
+	Upper    models.Trigram       `json:"upper"`               // 上卦（外卦）
+	Lower    models.Trigram       `json:"lower"`               // 下卦（内卦）
+	Judgment models.BilingualText `json:"judgment"`            // 卦辞
+	Image    models.BilingualText `json:"image"`               // 大象
+	Lines    []models.LineText    `json:"lines"`               // 六爻爻辞，自初爻至上爻
+	AllLines *models.LineText     `json:"all_lines,omitempty"` // 用九（乾）、用六（坤）
+}
+
+// hexagrams 按文王卦序排列的六十四卦
+var hexagrams []HexagramData
+
+// hexagramsByPattern 卦式到卦序的索引
+var hexagramsByPattern = make(map[string]int, 64)
+
+func init() {
+	if err := json.Unmarshal(hexagramsJSON, &hexagrams); err != nil {
+		panic(fmt.Sprintf("解析六十四卦资料失败: %v", err))
+	}
+	if err := validate(); err != nil {
+		panic(err)
+	}
+}
+
+// validate 校验卦序连续、卦式与上下卦一致、爻题与阴阳相符
+func validate() error {
+	if len(hexagrams) != 64 {
+		return fmt.Errorf("六十四卦资料应有64卦，实有%d卦", len(hexagrams))
+	}
+	for i, h := range hexagrams {
+		if h.Number != i+1 {
+			return fmt.Errorf("第%d条资料的卦序为%d", i+1, h.Number)
+		}
+		lower, _ := TrigramByName(h.Lower)
+		upper, _ := TrigramByName(h.Upper)
+		if h.Pattern != lower.Pattern+upper.Pattern {
+			return fmt.Errorf("%s卦的卦式%s与上%s下%s不符", h.Name, h.Pattern, h.Upper, h.Lower)
+		}
+		if len(h.Lines) != 6 {
+			return fmt.Errorf("%s卦应有六爻爻辞", h.Name)
+		}
+		for j, line := range h.Lines {
+			if line.Position != j+1 || line.Name != LineName(j+1, h.Pattern[j] == '1') {
+				return fmt.Errorf("%s卦第%d爻爻题“%s”有误", h.Name, j+1, line.Name)
+			}
+		}
+		if _, ok := hexagramsByPattern[h.Pattern]; ok {
+			return fmt.Errorf("%s卦的卦式%s重复", h.Name, h.Pattern)
+		}
+		hexagramsByPattern[h.Pattern] = h.Number
+	}
+	return nil
+}
+
+// Hexagrams 返回按文王卦序排列的六十四卦
+func Hexagrams() []HexagramData {
+	result := make([]HexagramData, len(hexagrams))
+	copy(result, hexagrams)
+	return result
+}
+
+// HexagramByNumber 按文王卦序查找
+func HexagramByNumber(number int) (*HexagramData, bool) {
+	if number < 1 || number > len(hexagrams) {
+		return nil, false
+	}
+	return &hexagrams[number-1], true
+}
+
+// HexagramByLines 由自下而上的六爻阴阳确定卦
+func HexagramByLines(yang []bool) *HexagramData {
+	number, ok := hexagramsByPattern[patternOf(yang)]
+	if !ok {
+		return nil
+	}
+	return &hexagrams[number-1]
+}
+
+// HexagramByTrigrams 由上下卦确定卦
+func HexagramByTrigrams(upper, lower models.Trigram) *HexagramData {
+	u, ok := TrigramByName(upper)
+	if !ok {
+		return nil
+	}
+	l, ok := TrigramByName(lower)
+	if !ok {
+		return nil
+	}
+	return &hexagrams[hexagramsByPattern[l.Pattern+u.Pattern]-1]
+}
+
+// Hexagram 转为占卜结果中使用的卦象摘要
+func (h *HexagramData) Hexagram() models.Hexagram {
+	return models.Hexagram{
+		Number: h.Number,
+		Name:   h.Name,
+		NameEn: h.NameEn,
+		Upper:  h.Upper,
+		Lower:  h.Lower,
+	}
+}
+
+// YangLines 自初爻至上爻的阴阳
+func (h *HexagramData) YangLines() []bool {
+	return linesOf(h.Pattern)
+}
+
+// LineName 爻题：阳称九、阴称六，初、上二爻位名在前，其余在后
+func LineName(position int, yang bool) string {
+	positions := [6]string{"初", "二", "三", "四", "五", "上"}
+	number := "六"
+	if yang {
+		number = "九"
+	}
+	if position == 1 || position == 6 {
+		return positions[position-1] + number
+	}
+	return number + positions[position-1]
+}
@@ -0,0 +1,162 @@
+package iching
+
+import "github.com/hobbyqhd/yijing/service/models"
+
+// najiaTrigram 八卦所纳天干与自下而上的三爻地支，分内卦、外卦
+type najiaTrigram struct {
+	InnerStem   models.Stem
+	InnerBranch [3]models.Branch
+	OuterStem   models.Stem
+	OuterBranch [3]models.Branch
+}
+
+// najiaTable 京房纳甲：乾纳甲壬，坤纳乙癸，震巽纳庚辛，坎离纳戊己，艮兑纳丙丁；
+// 阳卦地支顺行，阴卦地支逆行
+var najiaTable = map[models.Trigram]najiaTrigram{
+	models.Qian: {models.Jia, [3]models.Branch{models.Zi, models.Yin, models.Chen}, models.Ren, [3]models.Branch{models.Wu, models.Shen, models.Xu}},
+	models.Kun:  {models.Yi, [3]models.Branch{models.Wei, models.Si, models.Mao}, models.Gui, [3]models.Branch{models.Chou, models.Hai, models.You}},
+	models.Zhen: {models.Geng, [3]models.Branch{models.Zi, models.Yin, models.Chen}, models.Geng, [3]models.Branch{models.Wu, models.Shen, models.Xu}},
+	models.Xun:  {models.Xin, [3]models.Branch{models.Chou, models.Hai, models.You}, models.Xin, [3]models.Branch{models.Wei, models.Si, models.Mao}},
+	models.Kan:  {models.Wu4, [3]models.Branch{models.Yin, models.Chen, models.Wu}, models.Wu4, [3]models.Branch{models.Shen, models.Xu, models.Zi}},
+	models.Li:   {models.Ji, [3]models.Branch{models.Mao, models.Chou, models.Hai}, models.Ji, [3]models.Branch{models.You, models.Wei, models.Si}},
+	models.Gen:  {models.Bing, [3]models.Branch{models.Chen, models.Wu, models.Shen}, models.Bing, [3]models.Branch{models.Xu, models.Zi, models.Yin}},
+	models.Dui:  {models.Ding, [3]models.Branch{models.Si, models.Mao, models.Chou}, models.Ding, [3]models.Branch{models.Hai, models.You, models.Wei}},
+}
+
+// sixSpirits 六神自初爻起排列的次序
+var sixSpirits = [6]models.SixSpirit{
+	models.AzureDragon, models.VermilionBird, models.HookChen,
+	models.FlyingSerpent, models.WhiteTiger, models.BlackTortoise,
+}
+
+// palaceEntry 一卦在八宫中的归属
+type palaceEntry struct {
+	Palace     models.Trigram
+	Generation string
+	Shi        int
+}
+
+// palaces 卦式到八宫归属的索引
+var palaces = buildPalaces()
+
+// buildPalaces 按八宫卦变之序生成六十四卦的归属：本宫卦世在上爻，
+// 自初爻起逐爻变出一世至五世，五世卦四爻复变为游魂（世在四爻），
+// 游魂卦内卦复归本宫为归魂（世在三爻）
+func buildPalaces() map[string]palaceEntry {
+	generations := [5]string{"一世", "二世", "三世", "四世", "五世"}
+	result := make(map[string]palaceEntry, 64)
+	for _, t := range trigrams {
+		yang := linesOf(t.Pattern + t.Pattern)
+		result[patternOf(yang)] = palaceEntry{t.Name, "本宫", 6}
+		for i, generation := range generations {
+			yang[i] = !yang[i]
+			result[patternOf(yang)] = palaceEntry{t.Name, generation, i + 1}
+		}
+		yang[3] = !yang[3]
+		result[patternOf(yang)] = palaceEntry{t.Name, "游魂", 4}
+		copy(yang, t.Lines())
+		result[patternOf(yang)] = palaceEntry{t.Name, "归魂", 3}
+	}
+	return result
+}
+
+// Najia 六爻纳甲排盘：定卦宫与世应，纳天干地支，以卦宫五行论六亲，
+// 以起卦日干起六神，以日干支所在之旬定旬空
+func Najia(lines []models.LineValue, dayStem models.Stem, dayBranch models.Branch) *models.NajiaChart {
+	primary := make([]bool, len(lines))
+	changed := make([]bool, len(lines))
+	for i, v := range lines {
+		primary[i] = v.IsYang()
+		changed[i] = primary[i] != v.IsChanging()
+	}
+
+	entry := palaces[patternOf(primary)]
+	palace, _ := TrigramByName(entry.Palace)
+	chart := &models.NajiaChart{
+		Palace:        palace.Name,
+		PalaceElement: palace.Element,
+		Generation:    entry.Generation,
+		ShiLine:       entry.Shi,
+		YingLine:      (entry.Shi+2)%6 + 1,
+		DayStem:       dayStem,
+		DayBranch:     dayBranch,
+		Empty:         EmptyBranches(dayStem, dayBranch),
+	}
+
+	stems, branches := najiaLines(primary)
+	changedStems, changedBranches := najiaLines(changed)
+	spirit := firstSpirit(dayStem)
+	for i, v := range lines {
+		line := models.NajiaLine{
+			Position: i + 1,
+			Value:    v,
+			Stem:     stems[i],
+			Branch:   branches[i],
+			Element:  branches[i].Element(),
+			Relation: SixRelationOf(palace.Element, branches[i].Element()),
+			Spirit:   sixSpirits[(spirit+i)%6],
+			Shi:      i+1 == chart.ShiLine,
+			Ying:     i+1 == chart.YingLine,
+		}
+		for _, empty := range chart.Empty {
+			line.Empty = line.Empty || branches[i] == empty
+		}
+		if v.IsChanging() {
+			line.Changed = &models.NajiaChange{
+				Stem:     changedStems[i],
+				Branch:   changedBranches[i],
+				Element:  changedBranches[i].Element(),
+				Relation: SixRelationOf(palace.Element, changedBranches[i].Element()),
+			}
+		}
+		chart.Lines = append(chart.Lines, line)
+	}
+	return chart
+}
+
+// najiaLines 六爻所纳的天干地支：内卦取下三爻，外卦取上三爻
+func najiaLines(yang []bool) ([6]models.Stem, [6]models.Branch) {
+	var stems [6]models.Stem
+	var branches [6]models.Branch
+	inner := najiaTable[TrigramOfLines(yang[0:3])]
+	outer := najiaTable[TrigramOfLines(yang[3:6])]
+	for i := 0; i < 3; i++ {
+		stems[i], branches[i] = inner.InnerStem, inner.InnerBranch[i]
+		stems[i+3], branches[i+3] = outer.OuterStem, outer.OuterBranch[i]
+	}
+	return stems, branches
+}
+
+// firstSpirit 初爻六神：甲乙起青龙，丙丁起朱雀，戊起勾陈，己起螣蛇，庚辛起白虎，壬癸起玄武
+func firstSpirit(dayStem models.Stem) int {
+	starts := [10]int{0, 0, 1, 1, 2, 3, 4, 4, 5, 5}
+	if i := dayStem.Index(); i >= 0 {
+		return starts[i]
+	}
+	return 0
+}
+
+// SixRelationOf 以卦宫五行为“我”，求某一五行的六亲
+func SixRelationOf(self, other models.Element) models.SixRelation {
+	switch other {
+	case self:
+		return models.Brother
+	case self.Generates():
+		return models.Offspring
+	case self.Overcomes():
+		return models.Wealth
+	}
+	if other.Generates() == self {
+		return models.Parent
+	}
+	return models.Officer
+}
+
+// EmptyBranches 旬空：一旬十日配十支，所余两支为空亡
+func EmptyBranches(dayStem models.Stem, dayBranch models.Branch) []models.Branch {
+	start := dayBranch.Index() - dayStem.Index()
+	return []models.Branch{
+		models.Branches[(start+10+12)%12],
+		models.Branches[(start+11+12)%12],
+	}
+}
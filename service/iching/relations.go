@@ -0,0 +1,36 @@
+package iching
+
+import "github.com/hobbyqhd/yijing/service/models"
+
+// Nuclear 互卦（又称交互卦）：以二、三、四爻为下卦，三、四、五爻为上卦
+func Nuclear(h *HexagramData) *HexagramData {
+	yang := h.YangLines()
+	return HexagramByLines(append(yang[1:4:4], yang[2:5]...))
+}
+
+// Opposite 错卦（旁通卦）：六爻阴阳皆反
+func Opposite(h *HexagramData) *HexagramData {
+	yang := h.YangLines()
+	for i := range yang {
+		yang[i] = !yang[i]
+	}
+	return HexagramByLines(yang)
+}
+
+// Inverse 综卦（反卦）：全卦上下颠倒，初爻变为上爻
+func Inverse(h *HexagramData) *HexagramData {
+	yang := h.YangLines()
+	for i, j := 0, len(yang)-1; i < j; i, j = i+1, j-1 {
+		yang[i], yang[j] = yang[j], yang[i]
+	}
+	return HexagramByLines(yang)
+}
+
+// Derive 求一卦的互卦、错卦与综卦
+func Derive(h *HexagramData) models.DerivedHexagrams {
+	return models.DerivedHexagrams{
+		Nuclear:  Nuclear(h).Hexagram(),
+		Opposite: Opposite(h).Hexagram(),
+		Inverse:  Inverse(h).Hexagram(),
+	}
+}
@@ -0,0 +1,88 @@
+package iching
+
+import "github.com/hobbyqhd/yijing/service/models"
+
+// TrigramData 八卦（经卦）资料
+type TrigramData struct {
+	Name      models.Trigram `json:"name"`      // 卦名
+	NameEn    string         `json:"name_en"`   // 英文卦名
+	Symbol    string         `json:"symbol"`    // 卦符
+	Nature    string         `json:"nature"`    // 所象之物，如天、泽
+	NatureEn  string         `json:"nature_en"` // 所象之物的英文
+	Attribute string         `json:"attribute"` // 卦德，如健、顺
+	Family    string         `json:"family"`    // 家人之象，如父、长男
+	Element   models.Element `json:"element"`   // 所属五行
+	Xiantian  int            `json:"xiantian"`  // 先天八卦数（乾一至坤八）
+	Pattern   string         `json:"pattern"`   // 自下而上的阴阳爻，阳为1、阴为0
+}
+
+// trigrams 先天八卦数为序的八卦资料
+var trigrams = [8]TrigramData{
+	{Name: models.Qian, NameEn: "The Creative", Symbol: "☰", Nature: "天", NatureEn: "Heaven", Attribute: "健", Family: "父", Element: models.Metal, Xiantian: 1, Pattern: "111"},
+	{Name: models.Dui, NameEn: "The Joyous", Symbol: "☱", Nature: "泽", NatureEn: "Lake", Attribute: "说", Family: "少女", Element: models.Metal, Xiantian: 2, Pattern: "110"},
+	{Name: models.Li, NameEn: "The Clinging", Symbol: "☲", Nature: "火", NatureEn: "Fire", Attribute: "丽", Family: "中女", Element: models.Fire, Xiantian: 3, Pattern: "101"},
+	{Name: models.Zhen, NameEn: "The Arousing", Symbol: "☳", Nature: "雷", NatureEn: "Thunder", Attribute: "动", Family: "长男", Element: models.Wood, Xiantian: 4, Pattern: "100"},
+	{Name: models.Xun, NameEn: "The Gentle", Symbol: "☴", Nature: "风", NatureEn: "Wind", Attribute: "入", Family: "长女", Element: models.Wood, Xiantian: 5, Pattern: "011"},
+	{Name: models.Kan, NameEn: "The Abysmal", Symbol: "☵", Nature: "水", NatureEn: "Water", Attribute: "陷", Family: "中男", Element: models.Water, Xiantian: 6, Pattern: "010"},
+	{Name: models.Gen, NameEn: "Keeping Still", Symbol: "☶", Nature: "山", NatureEn: "Mountain", Attribute: "止", Family: "少男", Element: models.Earth, Xiantian: 7, Pattern: "001"},
+	{Name: models.Kun, NameEn: "The Receptive", Symbol: "☷", Nature: "地", NatureEn: "Earth", Attribute: "顺", Family: "母", Element: models.Earth, Xiantian: 8, Pattern: "000"},
+}
+
+// Trigrams 返回八卦资料，按先天八卦数排列
+func Trigrams() []TrigramData {
+	result := make([]TrigramData, len(trigrams))
+	copy(result, trigrams[:])
+	return result
+}
+
+// TrigramByName 按卦名查找八卦
+func TrigramByName(name models.Trigram) (TrigramData, bool) {
+	for _, t := range trigrams {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return TrigramData{}, false
+}
+
+// TrigramByXiantian 按先天八卦数取卦，数大于八时先除八取余（余零为坤）
+func TrigramByXiantian(number int) TrigramData {
+	return trigrams[((number-1)%8+8)%8]
+}
+
+// TrigramOfLines 由自下而上的三爻确定八卦
+func TrigramOfLines(yang []bool) models.Trigram {
+	pattern := patternOf(yang)
+	for _, t := range trigrams {
+		if t.Pattern == pattern {
+			return t.Name
+		}
+	}
+	return ""
+}
+
+// Lines 八卦自下而上的阴阳爻
+func (t TrigramData) Lines() []bool {
+	return linesOf(t.Pattern)
+}
+
+// patternOf 将阴阳爻转为由0、1组成的卦式
+func patternOf(yang []bool) string {
+	pattern := make([]byte, len(yang))
+	for i, isYang := range yang {
+		pattern[i] = '0'
+		if isYang {
+			pattern[i] = '1'
+		}
+	}
+	return string(pattern)
+}
+
+// linesOf 将卦式转为阴阳爻
+func linesOf(pattern string) []bool {
+	yang := make([]bool, len(pattern))
+	for i := range pattern {
+		yang[i] = pattern[i] == '1'
+	}
+	return yang
+}
@@ -0,0 +1,33 @@
+package models
+
+// DateSelection 择日结果
+type DateSelection struct {
+	Activity   string         `json:"activity"`              // 所择之事
+	From       string         `json:"from"`                  // 起始日期
+	To         string         `json:"to"`                    // 结束日期
+	YearBranch Branch         `json:"year_branch,omitempty"` // 事主生年地支（提供八字时）
+	DayBranch  Branch         `json:"day_branch,omitempty"`  // 事主日柱地支（提供八字时）
+	Days       []DayCandidate `json:"days"`                  // 可用之日，按得分由高到低排列
+	Rejected   []DayCandidate `json:"rejected"`              // 不取之日，按日期排列
+}
+
+// DayCandidate 择日的一个候选日
+type DayCandidate struct {
+	Date     string          `json:"date"`     // 公历日期
+	Lunar    string          `json:"lunar"`    // 农历日期
+	Ganzhi   string          `json:"ganzhi"`   // 日干支
+	Officer  string          `json:"officer"`  // 建除十二神
+	Spirit   string          `json:"spirit"`   // 值日黄道、黑道神
+	Score    int             `json:"score"`    // 得分
+	Accepted bool            `json:"accepted"` // 是否可用
+	Reasons  []string        `json:"reasons"`  // 取舍与加减分的依据
+	Hours    []HourCandidate `json:"hours"`    // 当日可用的吉时
+}
+
+// HourCandidate 候选吉时
+type HourCandidate struct {
+	Branch Branch `json:"branch"` // 时支
+	Ganzhi string `json:"ganzhi"` // 时干支
+	Range  string `json:"range"`  // 钟点
+	Spirit string `json:"spirit"` // 黄道神
+}
@@ -0,0 +1,230 @@
+package models
+
+import "math"
+
+// ZodiacSign 黄道十二星座
+type ZodiacSign string
+
+const (
+	Aries       ZodiacSign = "aries"
+	Taurus      ZodiacSign = "taurus"
+	Gemini      ZodiacSign = "gemini"
+	Cancer      ZodiacSign = "cancer"
+	Leo         ZodiacSign = "leo"
+	Virgo       ZodiacSign = "virgo"
+	Libra       ZodiacSign = "libra"
+	Scorpio     ZodiacSign = "scorpio"
+	Sagittarius ZodiacSign = "sagittarius"
+	Capricorn   ZodiacSign = "capricorn"
+	Aquarius    ZodiacSign = "aquarius"
+	Pisces      ZodiacSign = "pisces"
+)
+
+// ZodiacSigns 十二星座，自春分点起每30度一座
+var ZodiacSigns = [12]ZodiacSign{Aries, Taurus, Gemini, Cancer, Leo, Virgo,
+	Libra, Scorpio, Sagittarius, Capricorn, Aquarius, Pisces}
+
+// zodiacSignNames 十二星座的中文名
+var zodiacSignNames = [12]string{"白羊座", "金牛座", "双子座", "巨蟹座", "狮子座", "处女座",
+	"天秤座", "天蝎座", "射手座", "摩羯座", "水瓶座", "双鱼座"}
+
+// SignOf 黄经所在的星座
+func SignOf(longitude float64) ZodiacSign {
+	index := int(math.Floor(longitude/30)) % 12
+	if index < 0 {
+		index += 12
+	}
+	return ZodiacSigns[index]
+}
+
+// Index 星座序号，白羊座为0，无效时返回-1
+func (s ZodiacSign) Index() int {
+	for i, sign := range ZodiacSigns {
+		if sign == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// Name 星座的中文名
+func (s ZodiacSign) Name() string {
+	if i := s.Index(); i >= 0 {
+		return zodiacSignNames[i]
+	}
+	return string(s)
+}
+
+// CelestialBody 星盘中的天体
+type CelestialBody string
+
+const (
+	Sun     CelestialBody = "sun"
+	Moon    CelestialBody = "moon"
+	Mercury CelestialBody = "mercury"
+	Venus   CelestialBody = "venus"
+	Mars    CelestialBody = "mars"
+	Jupiter CelestialBody = "jupiter"
+	Saturn  CelestialBody = "saturn"
+	Uranus  CelestialBody = "uranus"
+	Neptune CelestialBody = "neptune"
+	Pluto   CelestialBody = "pluto"
+)
+
+// 上升点与天顶不是天体，但与天体一样参与相位
+const (
+	Ascendant CelestialBody = "ascendant"
+	Midheaven CelestialBody = "midheaven"
+)
+
+// CelestialBodies 星盘所含天体，按传统次序排列
+var CelestialBodies = [10]CelestialBody{Sun, Moon, Mercury, Venus, Mars, Jupiter, Saturn, Uranus, Neptune, Pluto}
+
+// celestialBodyNames 天体的中文名
+var celestialBodyNames = map[CelestialBody]string{
+	Sun: "太阳", Moon: "月亮", Mercury: "水星", Venus: "金星", Mars: "火星",
+	Jupiter: "木星", Saturn: "土星", Uranus: "天王星", Neptune: "海王星", Pluto: "冥王星",
+	Ascendant: "上升点", Midheaven: "天顶",
+}
+
+// Name 天体的中文名
+func (b CelestialBody) Name() string {
+	if name, ok := celestialBodyNames[b]; ok {
+		return name
+	}
+	return string(b)
+}
+
+// ChartPoint 黄道上的一点及其所在星座
+type ChartPoint struct {
+	Longitude float64    `json:"longitude"` // 黄经（度，当日春分点）
+	Sign      ZodiacSign `json:"sign"`      // 所在星座
+	SignName  string     `json:"signName"`  // 星座中文名
+	Degree    float64    `json:"degree"`    // 在星座内的度数（舍去两位小数以下）
+}
+
+// NewChartPoint 由黄经构造星盘上的点
+func NewChartPoint(longitude float64) ChartPoint {
+	sign := SignOf(longitude)
+	return ChartPoint{
+		Longitude: math.Round(longitude*10000) / 10000,
+		Sign:      sign,
+		SignName:  sign.Name(),
+		Degree:    math.Floor((longitude-float64(sign.Index())*30)*100) / 100,
+	}
+}
+
+// PlanetPosition 天体在星盘中的位置
+type PlanetPosition struct {
+	Body CelestialBody `json:"body"`
+	Name string        `json:"name"` // 天体中文名
+	ChartPoint
+	Latitude       float64 `json:"latitude"`                 // 黄纬（度）
+	Speed          float64 `json:"speed"`                    // 黄经日行度数，负值为逆行
+	Retrograde     bool    `json:"retrograde"`               // 是否逆行
+	House          int     `json:"house,omitempty"`          // 普拉西度制所落宫位
+	WholeSignHouse int     `json:"wholeSignHouse,omitempty"` // 整宫制所落宫位，行运天体无宫位
+}
+
+// HouseCusp 宫头（宫位起点）
+type HouseCusp struct {
+	House int `json:"house"`
+	ChartPoint
+}
+
+// HouseSystems 两种分宫制的宫头
+type HouseSystems struct {
+	Placidus  []HouseCusp `json:"placidus,omitempty"` // 普拉西度制，高纬度地区无法分宫时为空
+	WholeSign []HouseCusp `json:"wholeSign"`          // 整宫制
+}
+
+// NatalChart 西洋占星本命盘
+type NatalChart struct {
+	ClockTime  string           `json:"clockTime"`       // 出生地钟表时间
+	TimeZone   string           `json:"timeZone"`        // IANA时区名
+	UTC        string           `json:"utc"`             // 出生时刻（协调世界时）
+	Place      string           `json:"place,omitempty"` // 出生地
+	Longitude  float64          `json:"longitude"`       // 出生地经度，东经为正
+	Latitude   float64          `json:"latitude"`        // 出生地纬度，北纬为正
+	SunSign    ZodiacSign       `json:"sunSign"`         // 太阳星座
+	MoonSign   ZodiacSign       `json:"moonSign"`        // 月亮星座
+	RisingSign ZodiacSign       `json:"risingSign"`      // 上升星座
+	Ascendant  ChartPoint       `json:"ascendant"`       // 上升点
+	Midheaven  ChartPoint       `json:"midheaven"`       // 天顶
+	Planets    []PlanetPosition `json:"planets"`         // 各天体位置
+	Houses     HouseSystems     `json:"houses"`          // 宫头
+	Aspects    []Aspect         `json:"aspects"`         // 本命相位
+	Notes      []string         `json:"notes,omitempty"` // 计算说明
+}
+
+// AspectKind 相位
+type AspectKind string
+
+const (
+	Conjunction    AspectKind = "conjunction"    // 合相 0°
+	Opposition     AspectKind = "opposition"     // 对分相 180°
+	Trine          AspectKind = "trine"          // 三分相 120°
+	Square         AspectKind = "square"         // 四分相 90°
+	Sextile        AspectKind = "sextile"        // 六分相 60°
+	Quincunx       AspectKind = "quincunx"       // 梅花相 150°
+	SemiSextile    AspectKind = "semisextile"    // 半六分相 30°
+	SemiSquare     AspectKind = "semisquare"     // 半四分相 45°
+	Sesquiquadrate AspectKind = "sesquiquadrate" // 补八分相 135°
+	Quintile       AspectKind = "quintile"       // 五分相 72°
+	BiQuintile     AspectKind = "biquintile"     // 倍五分相 144°
+)
+
+// Aspect 两点之间的相位。本命盘中为两颗本命星，行运中First为行运星、Second为本命星，
+// 合盘中First、Second分属两人
+type Aspect struct {
+	First  CelestialBody `json:"first"`
+	Second CelestialBody `json:"second"`
+	Kind   AspectKind    `json:"kind"`
+	Name   string        `json:"name"`  // 相位中文名
+	Angle  float64       `json:"angle"` // 相位角（度）
+	Orb    float64       `json:"orb"`   // 与精确相位相差的度数
+	Major  bool          `json:"major"` // 是否为主要相位
+}
+
+// ZodiacReading 星座占卜结果：本命盘及占卜时刻行运星对本命盘的相位
+type ZodiacReading struct {
+	Natal    *NatalChart      `json:"natal"`    // 本命盘
+	Time     string           `json:"time"`     // 占卜时刻（出生地时区）
+	Sky      []PlanetPosition `json:"sky"`      // 占卜时刻各天体位置
+	Transits []Aspect         `json:"transits"` // 行运相位
+}
+
+// Transit 行运星与本命星在一段时间内形成的相位
+type Transit struct {
+	Transiting  CelestialBody `json:"transiting"`  // 行运星
+	Natal       CelestialBody `json:"natal"`       // 本命星
+	Kind        AspectKind    `json:"kind"`        // 相位
+	Name        string        `json:"name"`        // 相位中文名
+	Start       string        `json:"start"`       // 进入容许度的时刻，早于查询范围时取范围起点
+	End         string        `json:"end"`         // 离开容许度的时刻，晚于查询范围时取范围终点
+	Exact       []string      `json:"exact"`       // 相位精确的时刻，逆行时可能不止一次
+	Description string        `json:"description"` // 文字说明
+}
+
+// TransitReport 行运报告
+type TransitReport struct {
+	Natal    *NatalChart `json:"natal"`    // 本命盘
+	From     string      `json:"from"`     // 查询起始日期
+	To       string      `json:"to"`       // 查询结束日期
+	Transits []Transit   `json:"transits"` // 按开始时刻排列的行运
+}
+
+// HouseOverlay 一人的天体落在另一人星盘中的宫位
+type HouseOverlay struct {
+	Owner string        `json:"owner"` // 天体所属的一方：first或second
+	Body  CelestialBody `json:"body"`
+	House int           `json:"house"` // 在对方星盘中的宫位，对方无普拉西度宫位时按整宫制
+}
+
+// SynastryReading 合盘结果
+type SynastryReading struct {
+	First    *NatalChart    `json:"first"`
+	Second   *NatalChart    `json:"second"`
+	Aspects  []Aspect       `json:"aspects"`  // 两人星盘之间的相位，First为第一人的天体
+	Overlays []HouseOverlay `json:"overlays"` // 宫位叠加
+}
@@ -16,6 +16,33 @@ const (
 	Gui  Stem = "癸"
 )
 
+// Stems 十天干，以甲为首
+var Stems = [10]Stem{Jia, Yi, Bing, Ding, Wu4, Ji, Geng, Xin, Ren, Gui}
+
+// Index 天干序数（甲为0），非天干返回-1
+func (s Stem) Index() int {
+	for i, stem := range Stems {
+		if stem == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// IsYang 阳干：甲丙戊庚壬
+func (s Stem) IsYang() bool {
+	return s.Index()%2 == 0
+}
+
+// Element 天干五行：甲乙木、丙丁火、戊己土、庚辛金、壬癸水
+func (s Stem) Element() Element {
+	elements := [5]Element{Wood, Fire, Earth, Metal, Water}
+	if i := s.Index(); i >= 0 {
+		return elements[i/2]
+	}
+	return ""
+}
+
 // Branch 地支
 type Branch string
 
@@ -34,6 +61,28 @@ const (
 	Hai  Branch = "亥"
 )
 
+// Branches 十二地支，以子为首
+var Branches = [12]Branch{Zi, Chou, Yin, Mao, Chen, Si, Wu, Wei, Shen, You, Xu, Hai}
+
+// Index 地支序数（子为0），非地支返回-1
+func (b Branch) Index() int {
+	for i, branch := range Branches {
+		if branch == b {
+			return i
+		}
+	}
+	return -1
+}
+
+// Element 地支五行：寅卯木、巳午火、申酉金、亥子水，辰戌丑未土
+func (b Branch) Element() Element {
+	elements := [12]Element{Water, Earth, Wood, Wood, Earth, Fire, Fire, Earth, Metal, Metal, Earth, Water}
+	if i := b.Index(); i >= 0 {
+		return elements[i]
+	}
+	return ""
+}
+
 // Element 五行
 type Element string
 
@@ -45,6 +94,46 @@ const (
 	Water Element = "水"
 )
 
+// Generates 五行相生：木生火、火生土、土生金、金生水、水生木
+func (e Element) Generates() Element {
+	switch e {
+	case Wood:
+		return Fire
+	case Fire:
+		return Earth
+	case Earth:
+		return Metal
+	case Metal:
+		return Water
+	default:
+		return Wood
+	}
+}
+
+// Overcomes 五行相克：木克土、土克水、水克火、火克金、金克木
+func (e Element) Overcomes() Element {
+	switch e {
+	case Wood:
+		return Earth
+	case Earth:
+		return Water
+	case Water:
+		return Fire
+	case Fire:
+		return Metal
+	default:
+		return Wood
+	}
+}
+
+// ZiHourRule 子时换日规则
+type ZiHourRule string
+
+const (
+	ZiHourNextDay ZiHourRule = "next_day" // 23时起即属次日（早晚子不分）
+	ZiHourSameDay ZiHourRule = "same_day" // 23至24时为夜子时，日柱仍属当日，时干按次日推
+)
+
 // BaziPillar 八字柱（年月日时每柱包含天干和地支）
 type BaziPillar struct {
 	Stem   Stem   `json:"stem"`   // 天干
@@ -61,8 +150,232 @@ type BaziChart struct {
 
 // BaziReading 八字占卜结果
 type BaziReading struct {
-	Chart     BaziChart `json:"chart"`     // 八字命盘
-	DayMaster Stem      `json:"dayMaster"` // 日主（日柱天干）
-	Luck      string    `json:"luck"`      // 运势分析
-	Elements  []Element `json:"elements"`  // 五行分布
+	Chart      BaziChart  `json:"chart"`               // 八字命盘
+	DayMaster  Stem       `json:"dayMaster"`           // 日主（日柱天干）
+	Luck       string     `json:"luck"`                // 运势分析：旺衰、格局与喜忌的结论
+	Elements   []Element  `json:"elements"`            // 五行分布，按加权得分由强到弱排列
+	ZiHourRule ZiHourRule `json:"ziHourRule"`          // 排盘所用的子时换日规则
+	LunarBirth string     `json:"lunarBirth"`          // 农历生日，如庚午年闰五月十八
+	SolarTime  *SolarTime `json:"solarTime,omitempty"` // 真太阳时校正，未提供出生地时为空
+
+	Details       BaziDetails    `json:"details"`       // 四柱十神与地支藏干
+	ElementScores []ElementScore `json:"elementScores"` // 五行加权得分（计入月令旺衰）
+	Analysis      BaziAnalysis   `json:"analysis"`      // 日主旺衰、格局与用神
+
+	Interactions  []Interaction  `json:"interactions"`            // 四柱之间的干支作用
+	Symbols       *BaziSymbols   `json:"symbols,omitempty"`       // 纳音、十二长生与神煞，按需提供
+	Gender        Gender         `json:"gender,omitempty"`        // 性别，未提供时不排大运
+	LuckCycle     *LuckCycle     `json:"luckCycle,omitempty"`     // 大运
+	AnnualPillars []AnnualPillar `json:"annualPillars,omitempty"` // 流年
+}
+
+// Gender 性别
+type Gender string
+
+const (
+	Male   Gender = "male"
+	Female Gender = "female"
+)
+
+// LuckCycle 大运：阳年生男、阴年生女顺排，阴年生男、阳年生女逆排
+type LuckCycle struct {
+	Forward   bool         `json:"forward"`   // 是否顺排
+	Jie       string       `json:"jie"`       // 起运所依之节（顺排取出生后之节，逆排取出生前之节）
+	JieTime   string       `json:"jieTime"`   // 该节交节时刻
+	StartAge  AgeSpan      `json:"startAge"`  // 起运岁数：三日折一岁，一日折四月，一时辰折十日
+	StartTime string       `json:"startTime"` // 起运时间
+	Pillars   []LuckPillar `json:"pillars"`   // 各步大运
+}
+
+// AgeSpan 以年月日表示的岁数
+type AgeSpan struct {
+	Years  int `json:"years"`
+	Months int `json:"months"`
+	Days   int `json:"days"`
+}
+
+// LuckPillar 一步大运（十年）
+type LuckPillar struct {
+	Stem         Stem          `json:"stem"`         // 天干
+	Branch       Branch        `json:"branch"`       // 地支
+	TenGod       TenGod        `json:"tenGod"`       // 天干相对日主的十神
+	Interactions []Interaction `json:"interactions"` // 与原局四柱的干支作用
+	StartAge     int           `json:"startAge"`     // 起始周岁
+	StartYear    int           `json:"startYear"`    // 起始公历年
+	EndYear      int           `json:"endYear"`      // 结束公历年
+}
+
+// AnnualPillar 流年
+type AnnualPillar struct {
+	Year         int           `json:"year"`         // 公历年（以立春换年）
+	Stem         Stem          `json:"stem"`         // 天干
+	Branch       Branch        `json:"branch"`       // 地支
+	TenGod       TenGod        `json:"tenGod"`       // 天干相对日主的十神
+	Age          int           `json:"age"`          // 当年周岁（公历年减出生年）
+	Interactions []Interaction `json:"interactions"` // 与原局四柱的干支作用
+}
+
+// InteractionKind 干支作用的类别
+type InteractionKind string
+
+const (
+	StemCombination        InteractionKind = "天干五合"
+	SixCombination         InteractionKind = "六合"
+	ThreeHarmony           InteractionKind = "三合"
+	HalfHarmony            InteractionKind = "半合"
+	DirectionalCombination InteractionKind = "三会"
+	Clash                  InteractionKind = "六冲"
+	ThreePunishment        InteractionKind = "三刑"
+	MutualPunishment       InteractionKind = "相刑"
+	SelfPunishment         InteractionKind = "自刑"
+	Harm                   InteractionKind = "六害"
+	Destruction            InteractionKind = "破"
+)
+
+// PillarPosition 参与干支作用的柱
+type PillarPosition string
+
+const (
+	PositionYear   PillarPosition = "year"   // 年柱
+	PositionMonth  PillarPosition = "month"  // 月柱
+	PositionDay    PillarPosition = "day"    // 日柱
+	PositionHour   PillarPosition = "hour"   // 时柱
+	PositionLuck   PillarPosition = "luck"   // 大运
+	PositionAnnual PillarPosition = "annual" // 流年
+)
+
+// Interaction 干支之间的一项作用
+type Interaction struct {
+	Kind    InteractionKind  `json:"kind"`              // 类别
+	Pillars []PillarPosition `json:"pillars"`           // 参与的柱
+	Members []string         `json:"members"`           // 参与的天干或地支
+	Element Element          `json:"element,omitempty"` // 合化或会局所成的五行
+	Note    string           `json:"note,omitempty"`    // 补充说明，如刑的名目
+}
+
+// TenGod 十神，以日主为“我”论其余天干
+type TenGod string
+
+const (
+	DayMasterSelf    TenGod = "日主" // 日柱天干本身
+	Companion        TenGod = "比肩" // 同我、阴阳相同
+	RobWealth        TenGod = "劫财" // 同我、阴阳相异
+	EatingGod        TenGod = "食神" // 我生、阴阳相同
+	HurtingOfficer   TenGod = "伤官" // 我生、阴阳相异
+	IndirectWealth   TenGod = "偏财" // 我克、阴阳相同
+	DirectWealth     TenGod = "正财" // 我克、阴阳相异
+	SevenKillings    TenGod = "七杀" // 克我、阴阳相同
+	DirectOfficer    TenGod = "正官" // 克我、阴阳相异
+	IndirectResource TenGod = "偏印" // 生我、阴阳相同
+	DirectResource   TenGod = "正印" // 生我、阴阳相异
+)
+
+// HiddenStem 地支所藏天干
+type HiddenStem struct {
+	Stem   Stem    `json:"stem"`   // 天干
+	Role   string  `json:"role"`   // 本气、中气或余气
+	Weight float64 `json:"weight"` // 在该地支中所占的权重
+	TenGod TenGod  `json:"tenGod"` // 相对日主的十神
+}
+
+// PillarDetail 一柱的十神与藏干
+type PillarDetail struct {
+	TenGod      TenGod       `json:"tenGod"`      // 天干十神
+	HiddenStems []HiddenStem `json:"hiddenStems"` // 地支藏干
+}
+
+// BaziDetails 四柱的十神与藏干
+type BaziDetails struct {
+	Year  PillarDetail `json:"year"`  // 年柱
+	Month PillarDetail `json:"month"` // 月柱
+	Day   PillarDetail `json:"day"`   // 日柱
+	Hour  PillarDetail `json:"hour"`  // 时柱
+}
+
+// ElementScore 五行加权得分
+type ElementScore struct {
+	Element Element `json:"element"` // 五行
+	Score   float64 `json:"score"`   // 加权得分
+	Percent float64 `json:"percent"` // 占五行总分的百分比
+	Season  string  `json:"season"`  // 在月令中的旺衰：旺、相、休、囚、死
+}
+
+// SolarTime 出生时间的真太阳时校正
+type SolarTime struct {
+	ClockTime      string  `json:"clockTime"`       // 出生地钟表时间
+	TimeZone       string  `json:"timeZone"`        // IANA时区名
+	DST            bool    `json:"dst"`             // 钟表时间是否为夏令时
+	Place          string  `json:"place,omitempty"` // 出生地
+	Longitude      float64 `json:"longitude"`       // 经度，东经为正
+	Latitude       float64 `json:"latitude"`        // 纬度，北纬为正
+	EquationOfTime float64 `json:"equationOfTime"`  // 时差（真太阳时减平太阳时，分钟）
+	Correction     float64 `json:"correction"`      // 真太阳时减钟表时间（分钟）
+	ApparentTime   string  `json:"apparentTime"`    // 真太阳时
+}
+
+// BaziSymbols 命盘的纳音、日主十二长生与神煞
+type BaziSymbols struct {
+	Year    PillarSymbols `json:"year"`
+	Month   PillarSymbols `json:"month"`
+	Day     PillarSymbols `json:"day"`
+	Hour    PillarSymbols `json:"hour"`
+	ShenSha []ShenSha     `json:"shenSha"`
+}
+
+// PillarSymbols 一柱的纳音与日主在该柱地支的十二长生
+type PillarSymbols struct {
+	NaYin     string `json:"naYin"`     // 纳音五行，如海中金
+	LifeStage string `json:"lifeStage"` // 日主在此支的长生、沐浴等十二宫
+}
+
+// ShenSha 四柱中出现的一项神煞
+type ShenSha struct {
+	Name   string         `json:"name"`   // 神煞名
+	Pillar PillarPosition `json:"pillar"` // 所在之柱
+	Branch Branch         `json:"branch"` // 所在地支
+	Rule   string         `json:"rule"`   // 成立的依据，如“日干甲见丑”
+	Verse  string         `json:"verse"`  // 起例口诀
+}
+
+// StrengthLevel 日主旺衰的等级
+type StrengthLevel string
+
+const (
+	StrengthStrong   StrengthLevel = "身强"
+	StrengthBalanced StrengthLevel = "中和"
+	StrengthWeak     StrengthLevel = "身弱"
+	FollowStrong     StrengthLevel = "从强" // 日主极旺，无官杀制约
+	FollowWeak       StrengthLevel = "从弱" // 日主无根无助，从克泄耗之势
+)
+
+// BaziAnalysis 按规则推定的日主旺衰、格局与喜忌
+type BaziAnalysis struct {
+	Strength  DayMasterStrength `json:"strength"`  // 日主旺衰
+	Pattern   ChartPattern      `json:"pattern"`   // 格局
+	Favorable FavorableElements `json:"favorable"` // 用神、喜神、忌神
+	Trail     []string          `json:"trail"`     // 推断过程，依次记录每一步的依据
+}
+
+// DayMasterStrength 日主旺衰及其依据
+type DayMasterStrength struct {
+	Level    StrengthLevel `json:"level"`    // 等级
+	Support  float64       `json:"support"`  // 比劫与印星占五行总分的百分比
+	Season   string        `json:"season"`   // 日主在月令的旺相休囚死
+	Roots    []string      `json:"roots"`    // 日主通根之处，如“月支未（丁余气）”
+	Helpers  []string      `json:"helpers"`  // 透出天干的比劫、印星，如“时干甲（偏印）”
+	InSeason bool          `json:"inSeason"` // 是否得令（旺或相）
+}
+
+// ChartPattern 格局
+type ChartPattern struct {
+	Name    string `json:"name"`    // 格局名，如正官格、从财格
+	Special bool   `json:"special"` // 是否为从格、专旺等特殊格局
+	Basis   string `json:"basis"`   // 取格的依据
+}
+
+// FavorableElements 用神、喜神与忌神
+type FavorableElements struct {
+	Useful      Element   `json:"useful"`      // 用神
+	Favorable   []Element `json:"favorable"`   // 喜神
+	Unfavorable []Element `json:"unfavorable"` // 忌神
 }
@@ -0,0 +1,18 @@
+package models
+
+// CompatibilityReading 八字合婚结果
+type CompatibilityReading struct {
+	First   *BaziReading          `json:"first"`   // 甲方命盘
+	Second  *BaziReading          `json:"second"`  // 乙方命盘
+	Score   int                   `json:"score"`   // 总分（0-100）
+	Level   string                `json:"level"`   // 等第：上等、中等、下等
+	Aspects []CompatibilityAspect `json:"aspects"` // 各项评分
+}
+
+// CompatibilityAspect 合婚的一项评分
+type CompatibilityAspect struct {
+	Name     string   `json:"name"`     // 项目，如日主关系、夫妻宫
+	Score    int      `json:"score"`    // 得分
+	MaxScore int      `json:"maxScore"` // 满分
+	Findings []string `json:"findings"` // 评分依据
+}
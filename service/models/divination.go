@@ -13,6 +13,9 @@ const (
 	TypeTarot  DivinationType = "tarot"
 	TypeYijing DivinationType = "yijing"
 	TypeBazi   DivinationType = "bazi"
+
+	TypeCompatibility DivinationType = "compatibility" // 八字合婚
+	TypeSynastry      DivinationType = "synastry"      // 星盘合盘
 )
 
 type Divination struct {
@@ -24,14 +27,14 @@ type Divination struct {
 	Type       DivinationType `gorm:"type:varchar(20);column:type;not null"`
 	Question   string         `gorm:"type:text;column:question;not null"`
 	Input      string         `gorm:"type:text;column:input;not null"`
-	Result     string         `gorm:"type:text;column:result;not null"`
+	Result     string         `gorm:"type:mediumtext;column:result;not null"` // 合婚含双方命盘与流年，可能超出text的64KB上限
 	AIAnalysis string         `gorm:"type:text;column:ai_analysis"`
 }
 
 // IsValidDivinationType 验证占卜类型是否有效
 func IsValidDivinationType(t string) bool {
 	switch DivinationType(t) {
-	case TypeZodiac, TypeTarot, TypeYijing, TypeBazi:
+	case TypeZodiac, TypeTarot, TypeYijing, TypeBazi, TypeCompatibility, TypeSynastry:
 		return true
 	default:
 		return false
@@ -18,4 +18,5 @@ type Fortune struct {
 	WealthScore  int       `json:"wealth_score"`  // 财运指数
 	Analysis     string    `json:"analysis"`      // AI分析报告
 	Suggestions  string    `json:"suggestions"`   // 建议和注意事项
+	FromTransits bool      `json:"from_transits"` // 运势指数是否由行运相位推算，否则为随机生成
 }
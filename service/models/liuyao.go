@@ -0,0 +1,60 @@
+package models
+
+// SixRelation 六亲，以卦宫五行为“我”
+type SixRelation string
+
+const (
+	Brother   SixRelation = "兄弟" // 同我者
+	Offspring SixRelation = "子孙" // 我生者
+	Wealth    SixRelation = "妻财" // 我克者
+	Officer   SixRelation = "官鬼" // 克我者
+	Parent    SixRelation = "父母" // 生我者
+)
+
+// SixSpirit 六神（六兽）
+type SixSpirit string
+
+const (
+	AzureDragon   SixSpirit = "青龙"
+	VermilionBird SixSpirit = "朱雀"
+	HookChen      SixSpirit = "勾陈"
+	FlyingSerpent SixSpirit = "螣蛇"
+	WhiteTiger    SixSpirit = "白虎"
+	BlackTortoise SixSpirit = "玄武"
+)
+
+// NajiaChart 六爻纳甲排盘
+type NajiaChart struct {
+	Palace        Trigram     `json:"palace"`         // 所属八宫
+	PalaceElement Element     `json:"palace_element"` // 卦宫五行
+	Generation    string      `json:"generation"`     // 宫中次序：本宫、一世至五世、游魂、归魂
+	ShiLine       int         `json:"shi_line"`       // 世爻位置（1-6）
+	YingLine      int         `json:"ying_line"`      // 应爻位置（1-6）
+	DayStem       Stem        `json:"day_stem"`       // 起卦日干
+	DayBranch     Branch      `json:"day_branch"`     // 起卦日支
+	Empty         []Branch    `json:"empty"`          // 旬空地支
+	Lines         []NajiaLine `json:"lines"`          // 自初爻至上爻
+}
+
+// NajiaLine 纳甲排盘中的一爻
+type NajiaLine struct {
+	Position int          `json:"position"`          // 爻位（1-6）
+	Value    LineValue    `json:"value"`             // 爻值
+	Stem     Stem         `json:"stem"`              // 纳甲天干
+	Branch   Branch       `json:"branch"`            // 纳甲地支
+	Element  Element      `json:"element"`           // 地支五行
+	Relation SixRelation  `json:"relation"`          // 六亲
+	Spirit   SixSpirit    `json:"spirit"`            // 六神
+	Shi      bool         `json:"shi"`               // 是否为世爻
+	Ying     bool         `json:"ying"`              // 是否为应爻
+	Empty    bool         `json:"empty"`             // 是否旬空
+	Changed  *NajiaChange `json:"changed,omitempty"` // 动爻所化之爻
+}
+
+// NajiaChange 动爻所化之爻，六亲仍以本卦卦宫论
+type NajiaChange struct {
+	Stem     Stem        `json:"stem"`     // 纳甲天干
+	Branch   Branch      `json:"branch"`   // 纳甲地支
+	Element  Element     `json:"element"`  // 地支五行
+	Relation SixRelation `json:"relation"` // 六亲
+}
@@ -1,30 +1,139 @@
 package models
 
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
 // TarotCard 塔罗牌模型
 type TarotCard struct {
-	ID          int    `json:"id"`
-	Name        string `json:"name"`        // 牌名
-	NameEn      string `json:"name_en"`     // 英文名
-	Type        string `json:"type"`        // 大阿卡纳或小阿卡纳
-	Suit        string `json:"suit"`        // 如果是小阿卡纳，表示所属花色
-	Number      int    `json:"number"`      // 牌号
-	Description string `json:"description"` // 牌面描述
-	Upright     string `json:"upright"`     // 正位含义
-	Reversed    string `json:"reversed"`    // 逆位含义
-	ImageURL    string `json:"image_url"`   // 牌面图片URL
+	ID            int      `json:"id"`
+	Name          string   `json:"name"`           // 牌名
+	NameEn        string   `json:"name_en"`        // 英文名
+	Type          string   `json:"type"`           // 大阿卡纳或小阿卡纳
+	Suit          string   `json:"suit"`           // 如果是小阿卡纳，表示所属花色
+	Number        int      `json:"number"`         // 牌号，小阿卡纳王牌为1，侍从、骑士、王后、国王为11至14
+	Keywords      []string `json:"keywords"`       // 关键词
+	KeywordsEn    []string `json:"keywords_en"`    // 英文关键词
+	Description   string   `json:"description"`    // 牌面描述
+	DescriptionEn string   `json:"description_en"` // 英文牌面描述
+	Upright       string   `json:"upright"`        // 正位含义
+	UprightEn     string   `json:"upright_en"`     // 英文正位含义
+	Reversed      string   `json:"reversed"`       // 逆位含义
+	ReversedEn    string   `json:"reversed_en"`    // 英文逆位含义
+	ImageURL      string   `json:"image_url"`      // 牌面图片URL
 }
 
 // TarotSpread 塔罗牌阵模型
 type TarotSpread struct {
-	ID          int    `json:"id"`
-	Name        string `json:"name"`        // 牌阵名称
-	Description string `json:"description"` // 牌阵描述
-	Positions   int    `json:"positions"`   // 所需卡牌数量
+	ID          int              `json:"id"`
+	Name        string           `json:"name"`        // 牌阵名称
+	NameEn      string           `json:"name_en"`     // 英文名称
+	Description string           `json:"description"` // 牌阵描述
+	Positions   int              `json:"positions"`   // 所需卡牌数量
+	Layout      []SpreadPosition `json:"layout"`      // 各牌位，按摆放顺序排列
+}
+
+// SpreadPosition 牌阵中的一个牌位
+type SpreadPosition struct {
+	Index   int     `json:"index"`   // 摆放顺序，从1开始
+	Name    string  `json:"name"`    // 牌位名称
+	NameEn  string  `json:"name_en"` // 英文名称
+	Meaning string  `json:"meaning"` // 牌位所问之事
+	X       float64 `json:"x"`       // 横坐标，以牌宽为单位，自左向右
+	Y       float64 `json:"y"`       // 纵坐标，以牌高为单位，自上向下
+	Rotated bool    `json:"rotated"` // 是否横置，如凯尔特十字的第二张牌
+}
+
+// DrawnCard 落在某一牌位上的牌
+type DrawnCard struct {
+	Position SpreadPosition `json:"position"` // 牌位
+	Card     TarotCard      `json:"card"`     // 牌
+	Upright  bool           `json:"upright"`  // 是否正位
 }
 
 // TarotReading 塔罗牌占卜结果
 type TarotReading struct {
-	Cards     []TarotCard `json:"cards"`     // 抽取的卡牌
-	Positions []bool      `json:"positions"` // 每张牌是否正位
-	Spread    TarotSpread `json:"spread"`    // 使用的牌阵
+	Cards   []DrawnCard  `json:"cards"`          // 按牌位顺序排列的牌
+	Spread  TarotSpread  `json:"spread"`         // 使用的牌阵
+	Deck    string       `json:"deck,omitempty"` // 自定义牌组的名称，使用韦特塔罗时为空
+	Summary TarotSummary `json:"summary"`        // 牌阵概要
+}
+
+// TarotDeck 用户上传的自定义牌组
+type TarotDeck struct {
+	ID          uint           `gorm:"primaryKey;column:id;autoIncrement" json:"id"`
+	CreatedAt   time.Time      `gorm:"column:created_at;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index;column:deleted_at" json:"-"`
+	UserID      uint           `gorm:"index;column:user_id;not null" json:"user_id"`
+	Name        string         `gorm:"size:50;column:name;not null" json:"name"`
+	Description string         `gorm:"type:text;column:description" json:"description"`
+	Cards       TarotCardList  `gorm:"type:mediumtext;column:cards;not null" json:"cards"`
+}
+
+// TarotCardList 以JSON文本存入数据库的牌列表
+type TarotCardList []TarotCard
+
+// Value 实现driver.Valuer
+func (l TarotCardList) Value() (driver.Value, error) {
+	data, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan 实现sql.Scanner
+func (l *TarotCardList) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	case nil:
+		*l = nil
+		return nil
+	default:
+		return fmt.Errorf("无法解析牌列表: %T", value)
+	}
+}
+
+// TarotSummary 牌阵的结构化概要
+type TarotSummary struct {
+	Majors        int                `json:"majors"`         // 大阿卡纳张数
+	Courts        int                `json:"courts"`         // 宫廷牌张数
+	Numbers       int                `json:"numbers"`        // 数字牌（王牌至十）张数
+	Suits         []SuitCount        `json:"suits"`          // 各花色张数
+	DominantSuits []string           `json:"dominant_suits"` // 占主导的花色
+	Dignities     []ElementalDignity `json:"dignities"`      // 相邻牌位的元素关系
+	Pairs         []NotablePair      `json:"pairs"`          // 值得注意的牌组合
+	Notes         []string           `json:"notes"`          // 总体提示
+}
+
+// SuitCount 某一花色的张数
+type SuitCount struct {
+	Suit    string `json:"suit"`
+	Element string `json:"element"` // 花色对应的元素
+	Count   int    `json:"count"`
+}
+
+// ElementalDignity 相邻两张牌的元素关系：同元素相互加强，火风、水土相互扶持，
+// 火水、风土相互削弱，火土、风水中性
+type ElementalDignity struct {
+	Positions [2]int    `json:"positions"` // 两张牌的牌位序号
+	Cards     [2]string `json:"cards"`     // 两张牌的牌名
+	Elements  [2]string `json:"elements"`  // 两张牌的元素
+	Relation  string    `json:"relation"`  // 加强、扶持、削弱或中性
+}
+
+// NotablePair 牌阵中同时出现的两张牌构成的组合
+type NotablePair struct {
+	Positions [2]int    `json:"positions"` // 两张牌的牌位序号
+	Cards     [2]string `json:"cards"`     // 两张牌的牌名
+	Theme     string    `json:"theme"`     // 组合的主题
 }
@@ -0,0 +1,143 @@
+package models
+
+// LineValue 爻值，以揲蓍或掷钱所得的六、七、八、九表示
+type LineValue int
+
+const (
+	OldYin    LineValue = 6 // 老阴（变爻）
+	YoungYang LineValue = 7 // 少阳
+	YoungYin  LineValue = 8 // 少阴
+	OldYang   LineValue = 9 // 老阳（变爻）
+)
+
+// IsYang 是否为阳爻
+func (v LineValue) IsYang() bool {
+	return v == YoungYang || v == OldYang
+}
+
+// IsChanging 是否为变爻（老阴、老阳）
+func (v LineValue) IsChanging() bool {
+	return v == OldYin || v == OldYang
+}
+
+// Trigram 八卦（经卦）
+type Trigram string
+
+const (
+	Qian Trigram = "乾"
+	Dui  Trigram = "兑"
+	Li   Trigram = "离"
+	Zhen Trigram = "震"
+	Xun  Trigram = "巽"
+	Kan  Trigram = "坎"
+	Gen  Trigram = "艮"
+	Kun  Trigram = "坤"
+)
+
+// YijingMethod 起卦方式
+type YijingMethod string
+
+const (
+	MethodCoins       YijingMethod = "coins"        // 三钱法
+	MethodYarrow      YijingMethod = "yarrow"       // 大衍筮法
+	MethodPlumBlossom YijingMethod = "plum_blossom" // 梅花易数
+)
+
+// BilingualText 中英对照的经文
+type BilingualText struct {
+	Zh string `json:"zh"` // 原文
+	En string `json:"en"` // 英译
+}
+
+// LineText 爻辞
+type LineText struct {
+	Position int           `json:"position"` // 爻位（1-6，自下而上），用九、用六记为7
+	Name     string        `json:"name"`     // 爻题，如“初九”“六二”“用九”
+	Text     BilingualText `json:"text"`     // 爻辞
+}
+
+// Hexagram 六十四卦中的一卦
+type Hexagram struct {
+	Number int     `json:"number"`  // 文王卦序（1-64）
+	Name   string  `json:"name"`    // 卦名
+	NameEn string  `json:"name_en"` // 英文卦名
+	Upper  Trigram `json:"upper"`   // 上卦（外卦）
+	Lower  Trigram `json:"lower"`   // 下卦（内卦）
+}
+
+// YijingReading 易经占卜结果
+type YijingReading struct {
+	Method        YijingMethod     `json:"method"`              // 起卦方式
+	Lines         []LineValue      `json:"lines"`               // 自初爻至上爻的六个爻值
+	Primary       Hexagram         `json:"primary"`             // 本卦
+	ChangingLines []int            `json:"changing_lines"`      // 变爻位置（1-6，自下而上）
+	Resulting     *Hexagram        `json:"resulting,omitempty"` // 之卦，无变爻时为空
+	BodyUse       *BodyUse         `json:"body_use,omitempty"`  // 梅花易数的体用关系
+	Derived       DerivedHexagrams `json:"derived"`             // 本卦的互卦、错卦、综卦
+	Najia         *NajiaChart      `json:"najia,omitempty"`     // 六爻纳甲排盘
+
+	Judgment          BilingualText  `json:"judgment"`                     // 本卦卦辞
+	Image             BilingualText  `json:"image"`                        // 本卦大象
+	LineTexts         []LineText     `json:"line_texts"`                   // 变爻爻辞，乾坤六爻皆变时为用九、用六
+	ResultingJudgment *BilingualText `json:"resulting_judgment,omitempty"` // 之卦卦辞
+}
+
+// DerivedHexagrams 由本卦推出的相关卦
+type DerivedHexagrams struct {
+	Nuclear  Hexagram `json:"nuclear"`  // 互卦
+	Opposite Hexagram `json:"opposite"` // 错卦
+	Inverse  Hexagram `json:"inverse"`  // 综卦
+}
+
+// BodyUse 梅花易数的体用关系：动爻所在之卦为用，另一卦为体
+type BodyUse struct {
+	Body        Trigram `json:"body"`         // 体卦
+	Use         Trigram `json:"use"`          // 用卦
+	BodyElement Element `json:"body_element"` // 体卦五行
+	UseElement  Element `json:"use_element"`  // 用卦五行
+	Relation    string  `json:"relation"`     // 体用生克，如“用生体”
+	Verdict     string  `json:"verdict"`      // 吉凶断语
+}
+
+// CoinToss 三钱法一爻的投掷记录
+type CoinToss struct {
+	Coins []int     `json:"coins"` // 三枚铜钱的计数，字为2、背为3
+	Value LineValue `json:"value"` // 所得爻值
+}
+
+// YarrowChange 大衍筮法中的一变
+type YarrowChange struct {
+	Stalks         int `json:"stalks"`          // 本变所用蓍草数
+	Left           int `json:"left"`            // 分二后左手（天）策数
+	Right          int `json:"right"`           // 分二后右手（地）策数，含挂一之策
+	LeftRemainder  int `json:"left_remainder"`  // 左手揲四之余
+	RightRemainder int `json:"right_remainder"` // 右手揲四之余
+	Removed        int `json:"removed"`         // 挂一与两手余策之和（归奇）
+}
+
+// YarrowLine 大衍筮法一爻的三变记录
+type YarrowLine struct {
+	Changes   []YarrowChange `json:"changes"`   // 三变
+	Remaining int            `json:"remaining"` // 三变后所余策数
+	Value     LineValue      `json:"value"`     // 所得爻值（余策除以四）
+}
+
+// YijingCasting 起卦过程记录，保存在占卜记录的输入中以备查验
+type YijingCasting struct {
+	Method      YijingMethod        `json:"method"`                 // 起卦方式
+	Coins       []CoinToss          `json:"coins,omitempty"`        // 三钱法逐爻记录
+	Yarrow      []YarrowLine        `json:"yarrow,omitempty"`       // 大衍筮法逐爻记录
+	PlumBlossom *PlumBlossomCasting `json:"plum_blossom,omitempty"` // 梅花易数起卦记录
+}
+
+// PlumBlossomCasting 梅花易数起卦记录
+type PlumBlossomCasting struct {
+	Source       string `json:"source"`          // 起卦依据：time、numbers或text
+	Lunar        string `json:"lunar,omitempty"` // 时间起卦所用的农历日期与时辰
+	Text         string `json:"text,omitempty"`  // 字占所用的文字
+	Numbers      []int  `json:"numbers"`         // 参与起卦的各数（年月日时数、所报之数或笔画数）
+	UpperNumber  int    `json:"upper_number"`    // 取上卦之数
+	LowerNumber  int    `json:"lower_number"`    // 取下卦之数
+	MovingNumber int    `json:"moving_number"`   // 取动爻之数
+	MovingLine   int    `json:"moving_line"`     // 动爻（1-6）
+}
@@ -27,9 +27,50 @@ func RegisterRoutes(r *gin.Engine) {
 		// 应用认证中间件
 		authorized := divinationGroup.Use(middleware.Auth())
 		authorized.POST("", divinationHandler.CreateDivination)
+		authorized.POST("/compatibility", divinationHandler.CreateCompatibility)
+		authorized.POST("/synastry", divinationHandler.CreateSynastry)
 		authorized.GET("/history", divinationHandler.GetUserDivinations)
 	}
 
+	// 塔罗自定义牌组路由
+	tarotGroup := r.Group("/tarot").Use(middleware.Auth())
+	{
+		tarotHandler := handlers.NewTarotHandler()
+		tarotGroup.POST("/decks", tarotHandler.CreateDeck)
+		tarotGroup.GET("/decks", tarotHandler.GetUserDecks)
+		tarotGroup.GET("/decks/:id", tarotHandler.GetDeck)
+		tarotGroup.DELETE("/decks/:id", tarotHandler.DeleteDeck)
+	}
+
+	// 地点检索路由（无需认证）
+	geoGroup := r.Group("/geo")
+	{
+		geoHandler := handlers.NewGeoHandler()
+		geoGroup.GET("/search", geoHandler.SearchCities)
+	}
+
+	// 历法查询路由（无需认证）
+	calendarGroup := r.Group("/calendar")
+	{
+		calendarHandler := handlers.NewCalendarHandler()
+		calendarGroup.GET("/lunar", calendarHandler.GetLunar)
+	}
+
+	// 黄历路由（无需认证）
+	almanacGroup := r.Group("/almanac")
+	{
+		almanacHandler := handlers.NewAlmanacHandler()
+		almanacGroup.GET("/:date", almanacHandler.GetAlmanac)
+		almanacGroup.POST("/select", almanacHandler.SelectDates)
+	}
+
+	// 占星路由（无需认证）
+	astrologyGroup := r.Group("/astrology")
+	{
+		astrologyHandler := handlers.NewAstrologyHandler()
+		astrologyGroup.POST("/transits", astrologyHandler.Transits)
+	}
+
 	// 运势分析相关路由
 	fortuneGroup := r.Group("/fortune").Use(middleware.Auth())
 	{
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hobbyqhd/yijing/service/almanac"
+	"github.com/hobbyqhd/yijing/service/config"
+)
+
+// almanacCacheTTL 黄历缓存的有效期。黄历由日期唯一确定，缓存只为省去节气与朔望的推算
+const almanacCacheTTL = 30 * 24 * time.Hour
+
+// almanacDateLayout 黄历日期格式
+const almanacDateLayout = "2006-01-02"
+
+type AlmanacService struct{}
+
+func NewAlmanacService() *AlmanacService {
+	return &AlmanacService{}
+}
+
+// GetAlmanac 查询某日黄历，优先读取Redis缓存，缓存不可用时直接推算
+func (s *AlmanacService) GetAlmanac(date string) (*almanac.Almanac, error) {
+	day, err := time.Parse(almanacDateLayout, date)
+	if err != nil {
+		return nil, fmt.Errorf("日期格式应为2006-01-02")
+	}
+	key := "almanac:" + day.Format(almanacDateLayout)
+	ctx := context.Background()
+
+	if config.RedisClient != nil {
+		if data, err := config.RedisClient.Get(ctx, key).Bytes(); err == nil {
+			var cached almanac.Almanac
+			if json.Unmarshal(data, &cached) == nil {
+				return &cached, nil
+			}
+		}
+	}
+
+	result, err := almanac.Of(day.Year(), day.Month(), day.Day())
+	if err != nil {
+		return nil, err
+	}
+
+	// 写入缓存失败不影响查询结果
+	if config.RedisClient != nil {
+		if data, err := json.Marshal(result); err == nil {
+			config.RedisClient.Set(ctx, key, data, almanacCacheTTL)
+		}
+	}
+	return result, nil
+}
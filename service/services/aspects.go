@@ -0,0 +1,187 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/hobbyqhd/yijing/service/models"
+)
+
+// aspectDef 相位的定义及默认容许度
+type aspectDef struct {
+	Kind  models.AspectKind
+	Name  string
+	Angle float64
+	Orb   float64 // 本命盘与合盘的默认容许度（度）
+	Major bool
+}
+
+// aspectDefs 五种主要相位与六种次要相位
+var aspectDefs = []aspectDef{
+	{models.Conjunction, "合相", 0, 8, true},
+	{models.Opposition, "对分相", 180, 8, true},
+	{models.Trine, "三分相", 120, 7, true},
+	{models.Square, "四分相", 90, 7, true},
+	{models.Sextile, "六分相", 60, 5, true},
+	{models.Quincunx, "梅花相", 150, 3, false},
+	{models.SemiSextile, "半六分相", 30, 2, false},
+	{models.SemiSquare, "半四分相", 45, 2, false},
+	{models.Sesquiquadrate, "补八分相", 135, 2, false},
+	{models.Quintile, "五分相", 72, 2, false},
+	{models.BiQuintile, "倍五分相", 144, 2, false},
+}
+
+// transitOrb 行运相位的默认容许度
+const transitOrb = 1.0
+
+// maxOrb 容许度上限
+const maxOrb = 15.0
+
+// AspectOptions 相位的计算选项
+type AspectOptions struct {
+	Orbs  map[models.AspectKind]float64 `json:"orbs,omitempty"`  // 各相位的容许度（度），覆盖默认值
+	Minor bool                          `json:"minor,omitempty"` // 是否计入次要相位
+}
+
+// activeAspects 按选项确定参与计算的相位及其容许度；defaultOrb大于0时代替各相位的默认容许度
+func activeAspects(options AspectOptions, defaultOrb float64) ([]aspectDef, error) {
+	known := make(map[models.AspectKind]bool, len(aspectDefs))
+	for _, def := range aspectDefs {
+		known[def.Kind] = true
+	}
+	for kind, orb := range options.Orbs {
+		if !known[kind] {
+			return nil, fmt.Errorf("不支持的相位: %s", kind)
+		}
+		if orb < 0 || orb > maxOrb {
+			return nil, fmt.Errorf("相位容许度应在0至%.0f度之间", maxOrb)
+		}
+	}
+
+	defs := make([]aspectDef, 0, len(aspectDefs))
+	for _, def := range aspectDefs {
+		if !def.Major && !options.Minor {
+			continue
+		}
+		if defaultOrb > 0 {
+			def.Orb = defaultOrb
+		}
+		if orb, ok := options.Orbs[def.Kind]; ok {
+			def.Orb = orb
+		}
+		if def.Orb > 0 {
+			defs = append(defs, def)
+		}
+	}
+	return defs, nil
+}
+
+// separation 两黄经之间的夹角，取值0至180度
+func separation(a, b float64) float64 {
+	d := math.Mod(math.Abs(a-b), 360)
+	if d > 180 {
+		d = 360 - d
+	}
+	return d
+}
+
+// matchAspect 两黄经构成的最紧密的相位
+func matchAspect(a, b float64, defs []aspectDef) (aspectDef, float64, bool) {
+	sep := separation(a, b)
+	var best aspectDef
+	bestOrb, found := 0.0, false
+	for _, def := range defs {
+		orb := math.Abs(sep - def.Angle)
+		if orb <= def.Orb && (!found || orb < bestOrb) {
+			best, bestOrb, found = def, orb, true
+		}
+	}
+	return best, bestOrb, found
+}
+
+// chartPoint 参与相位计算的点
+type chartPoint struct {
+	Body      models.CelestialBody
+	Longitude float64
+}
+
+// natalPoints 本命盘中参与相位的点：十颗天体与上升点、天顶
+func natalPoints(chart *models.NatalChart) []chartPoint {
+	points := make([]chartPoint, 0, len(chart.Planets)+2)
+	for _, p := range chart.Planets {
+		points = append(points, chartPoint{p.Body, p.Longitude})
+	}
+	return append(points,
+		chartPoint{models.Ascendant, chart.Ascendant.Longitude},
+		chartPoint{models.Midheaven, chart.Midheaven.Longitude})
+}
+
+// skyPoints 某一时刻的天体位置
+func skyPoints(positions []models.PlanetPosition) []chartPoint {
+	points := make([]chartPoint, 0, len(positions))
+	for _, p := range positions {
+		points = append(points, chartPoint{p.Body, p.Longitude})
+	}
+	return points
+}
+
+// newAspect 构造相位记录
+func newAspect(first, second models.CelestialBody, def aspectDef, orb float64) models.Aspect {
+	return models.Aspect{
+		First:  first,
+		Second: second,
+		Kind:   def.Kind,
+		Name:   def.Name,
+		Angle:  def.Angle,
+		Orb:    math.Round(orb*100) / 100,
+		Major:  def.Major,
+	}
+}
+
+// mutualAspects 同一组点两两之间的相位。上升点与天顶之间的夹角由纬度决定，不计
+func mutualAspects(points []chartPoint, defs []aspectDef) []models.Aspect {
+	aspects := make([]models.Aspect, 0)
+	for i := range points {
+		for j := i + 1; j < len(points); j++ {
+			a, b := points[i], points[j]
+			if a.Body == models.Ascendant && b.Body == models.Midheaven {
+				continue
+			}
+			if def, orb, ok := matchAspect(a.Longitude, b.Longitude, defs); ok {
+				aspects = append(aspects, newAspect(a.Body, b.Body, def, orb))
+			}
+		}
+	}
+	return sortAspects(aspects)
+}
+
+// crossAspects 两组点之间的相位，First取自第一组
+func crossAspects(first, second []chartPoint, defs []aspectDef) []models.Aspect {
+	aspects := make([]models.Aspect, 0)
+	for _, a := range first {
+		for _, b := range second {
+			if def, orb, ok := matchAspect(a.Longitude, b.Longitude, defs); ok {
+				aspects = append(aspects, newAspect(a.Body, b.Body, def, orb))
+			}
+		}
+	}
+	return sortAspects(aspects)
+}
+
+// sortAspects 主要相位在前，同类按容许度由紧到松排列
+func sortAspects(aspects []models.Aspect) []models.Aspect {
+	sort.SliceStable(aspects, func(i, j int) bool {
+		if aspects[i].Major != aspects[j].Major {
+			return aspects[i].Major
+		}
+		return aspects[i].Orb < aspects[j].Orb
+	})
+	return aspects
+}
+
+// aspectText 相位的文字表述，如“太阳四分相月亮（容许度2.15°）”
+func aspectText(a models.Aspect, firstPrefix, secondPrefix string) string {
+	return fmt.Sprintf("%s%s%s%s%s（容许度%.2f°）",
+		firstPrefix, a.First.Name(), a.Name, secondPrefix, a.Second.Name(), a.Orb)
+}
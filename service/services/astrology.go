@@ -0,0 +1,342 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hobbyqhd/yijing/service/astro"
+	"github.com/hobbyqhd/yijing/service/models"
+)
+
+type AstrologyService struct{}
+
+func NewAstrologyService() *AstrologyService {
+	return &AstrologyService{}
+}
+
+// TransitInput 行运报告的输入参数
+type TransitInput struct {
+	Natal NatalInput `json:"natal"`          // 出生信息
+	From  string     `json:"from,omitempty"` // 起始日期，格式为2006-01-02，默认为今天
+	To    string     `json:"to,omitempty"`   // 结束日期，默认为起始日期后30天
+	AspectOptions
+}
+
+// SynastryInput 合盘的输入参数，双方出生信息的格式与星座占卜的input相同
+type SynastryInput struct {
+	First  interface{} `json:"first"`
+	Second interface{} `json:"second"`
+	AspectOptions
+}
+
+// maxTransitDays 一次最多查询的行运天数
+const maxTransitDays = 366
+
+// transitStep 行运的采样间隔，各相位的出入与精确时刻再经二分求精
+const transitStep = 6 * time.Hour
+
+// transitTimeLayout 行运时刻的显示格式
+const transitTimeLayout = "2006-01-02 15:04"
+
+// transitBodies 计入行运报告的天体。月亮约两天半换一个星座，影响短暂，不计入
+var transitBodies = []models.CelestialBody{models.Sun, models.Mercury, models.Venus, models.Mars,
+	models.Jupiter, models.Saturn, models.Uranus, models.Neptune, models.Pluto}
+
+// zodiacReading 星座占卜：排本命盘，并求占卜时刻各天体对本命盘的行运相位
+func zodiacReading(input interface{}, now time.Time) (*models.ZodiacReading, error) {
+	chart, err := natalChart(input)
+	if err != nil {
+		return nil, &InputError{err}
+	}
+	defs, _ := activeAspects(AspectOptions{}, transitOrb)
+	location, _ := time.LoadLocation(chart.TimeZone)
+	sky := planetPositions(astro.ToDynamicalTime(astro.JulianDay(now)))
+	return &models.ZodiacReading{
+		Natal:    chart,
+		Time:     now.In(location).Format(transitTimeLayout),
+		Sky:      sky,
+		Transits: crossAspects(skyPoints(sky), natalPoints(chart), defs),
+	}, nil
+}
+
+// Transits 行运报告：在日期范围内逐段采样各行运星的位置，找出其与本命星形成相位的起止与精确时刻
+func (s *AstrologyService) Transits(input *TransitInput) (*models.TransitReport, error) {
+	chart, err := natalChart(input.Natal)
+	if err != nil {
+		return nil, err
+	}
+	defs, err := activeAspects(input.AspectOptions, transitOrb)
+	if err != nil {
+		return nil, err
+	}
+
+	// 日期按出生地时区解释
+	location, _ := time.LoadLocation(chart.TimeZone)
+	from := time.Now().In(location)
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, location)
+	if input.From != "" {
+		if from, err = time.ParseInLocation(almanacDateLayout, input.From, location); err != nil {
+			return nil, fmt.Errorf("起始日期格式错误，应为2006-01-02")
+		}
+	}
+	to := from.AddDate(0, 0, 30)
+	if input.To != "" {
+		if to, err = time.ParseInLocation(almanacDateLayout, input.To, location); err != nil {
+			return nil, fmt.Errorf("结束日期格式错误，应为2006-01-02")
+		}
+	}
+	if to.Before(from) || to.Sub(from) > maxTransitDays*24*time.Hour {
+		return nil, fmt.Errorf("日期范围有误，最多查询%d天", maxTransitDays)
+	}
+	if err := checkPlanetYear(from); err != nil {
+		return nil, err
+	}
+	if err := checkPlanetYear(to); err != nil {
+		return nil, err
+	}
+	// 结束日期当天全天计入
+	end := to.AddDate(0, 0, 1)
+
+	var times []time.Time
+	for t := from; !t.After(end); t = t.Add(transitStep) {
+		times = append(times, t)
+	}
+	longitudes := make(map[models.CelestialBody][]float64, len(transitBodies))
+	for _, body := range transitBodies {
+		series := make([]float64, len(times))
+		for i, t := range times {
+			series[i], _ = bodyPosition(body, astro.ToDynamicalTime(astro.JulianDay(t)))
+		}
+		longitudes[body] = series
+	}
+
+	report := &models.TransitReport{
+		Natal:    chart,
+		From:     from.Format(almanacDateLayout),
+		To:       to.Format(almanacDateLayout),
+		Transits: make([]models.Transit, 0),
+	}
+	for _, body := range transitBodies {
+		for _, natal := range natalPoints(chart) {
+			for _, def := range defs {
+				report.Transits = append(report.Transits,
+					findTransits(body, natal, def, times, longitudes[body])...)
+			}
+		}
+	}
+	sort.SliceStable(report.Transits, func(i, j int) bool {
+		return report.Transits[i].Start < report.Transits[j].Start
+	})
+	return report, nil
+}
+
+// findTransits 在采样序列中找出行运星与本命点形成某一相位的各段时间。
+// 除合相与对分相外，相位分入相前后两侧（如上弦与下弦的四分相），分别计算。
+// 每一步都检查偏离是否变号，容许度小于两次采样间的行度时，整段相位可能落在两次采样之间，
+// 此时以精确时刻为界向两侧二分求出入容许度的时刻
+func findTransits(body models.CelestialBody, natal chartPoint, def aspectDef, times []time.Time, series []float64) []models.Transit {
+	targets := []float64{def.Angle}
+	if def.Angle != 0 && def.Angle != 180 {
+		targets = append(targets, -def.Angle)
+	}
+
+	var transits []models.Transit
+	for _, target := range targets {
+		// signed 行运星在t时刻相对精确相位的偏离，取值-180至180度
+		signed := func(t time.Time) float64 {
+			lon, _ := bodyPosition(body, astro.ToDynamicalTime(astro.JulianDay(t)))
+			return math.Mod(lon-natal.Longitude-target+540, 360) - 180
+		}
+		// outside 偏离超出容许度的度数，在容许度内为负
+		outside := func(t time.Time) float64 {
+			return math.Abs(signed(t)) - def.Orb
+		}
+		deviation := func(i int) float64 {
+			return math.Mod(series[i]-natal.Longitude-target+540, 360) - 180
+		}
+
+		var current *models.Transit
+		open := func(t time.Time) {
+			current = &models.Transit{
+				Transiting: body,
+				Natal:      natal.Body,
+				Kind:       def.Kind,
+				Name:       def.Name,
+				Start:      t.Format(transitTimeLayout),
+				Exact:      make([]string, 0, 1),
+			}
+		}
+		closeAt := func(t time.Time) {
+			current.End = t.Format(transitTimeLayout)
+			current.Description = transitText(*current)
+			transits = append(transits, *current)
+			current = nil
+		}
+
+		for i := range times {
+			e := deviation(i)
+			inOrb := math.Abs(e) <= def.Orb
+			if i == 0 {
+				if inOrb {
+					open(times[0])
+				}
+				continue
+			}
+
+			prev := deviation(i - 1)
+			// 偏离变号即经过精确相位；相差近360度的变号是在对侧绕过，不算
+			crossed := (prev < 0) != (e < 0) && math.Abs(prev-e) < 90
+			var exact time.Time
+			if crossed {
+				exact = bisectTime(times[i-1], times[i], signed)
+			}
+
+			switch {
+			case current == nil && inOrb:
+				open(bisectTime(times[i-1], times[i], outside))
+				if crossed {
+					current.Exact = append(current.Exact, exact.Format(transitTimeLayout))
+				}
+			case current == nil && crossed:
+				// 整段容许度落在两次采样之间
+				open(bisectTime(times[i-1], exact, outside))
+				current.Exact = append(current.Exact, exact.Format(transitTimeLayout))
+				closeAt(bisectTime(exact, times[i], outside))
+			case current != nil:
+				if crossed {
+					current.Exact = append(current.Exact, exact.Format(transitTimeLayout))
+				}
+				if !inOrb {
+					closeAt(bisectTime(times[i-1], times[i], outside))
+				}
+			}
+			if current != nil && i == len(times)-1 {
+				closeAt(times[i])
+			}
+		}
+	}
+	return transits
+}
+
+// bisectTime 二分求f在t0至t1之间变号的时刻，精确到一分钟。调用方保证f在两端异号
+func bisectTime(t0, t1 time.Time, f func(time.Time) float64) time.Time {
+	negative := f(t0) < 0
+	for t1.Sub(t0) > time.Minute {
+		mid := t0.Add(t1.Sub(t0) / 2)
+		if (f(mid) < 0) == negative {
+			t0 = mid
+		} else {
+			t1 = mid
+		}
+	}
+	return t0.Add(t1.Sub(t0) / 2)
+}
+
+// transitText 行运的文字表述，如“行运土星四分相本命太阳，2026-01-03 08:00至2026-03-05 20:00，精确于2026-02-01 12:00”
+func transitText(t models.Transit) string {
+	text := fmt.Sprintf("行运%s%s本命%s，%s至%s", t.Transiting.Name(), t.Name, t.Natal.Name(), t.Start, t.End)
+	if len(t.Exact) > 0 {
+		text += "，精确于" + strings.Join(t.Exact, "、")
+	}
+	return text
+}
+
+// synastry 合盘：两人星盘之间的相位，以及各自的天体落在对方星盘中的宫位
+func synastry(input interface{}) (*models.SynastryReading, error) {
+	var params SynastryInput
+	if err := decodeInput(input, &params); err != nil {
+		return nil, &InputError{fmt.Errorf("合盘参数格式错误")}
+	}
+	if params.First == nil || params.Second == nil {
+		return nil, &InputError{fmt.Errorf("合盘需要提供双方的出生信息")}
+	}
+	first, err := natalChart(params.First)
+	if err != nil {
+		return nil, &InputError{fmt.Errorf("第一人：%v", err)}
+	}
+	second, err := natalChart(params.Second)
+	if err != nil {
+		return nil, &InputError{fmt.Errorf("第二人：%v", err)}
+	}
+	defs, err := activeAspects(params.AspectOptions, 0)
+	if err != nil {
+		return nil, &InputError{err}
+	}
+
+	reading := &models.SynastryReading{
+		First:   first,
+		Second:  second,
+		Aspects: crossAspects(natalPoints(first), natalPoints(second), defs),
+	}
+	reading.Overlays = append(houseOverlays("first", first, second), houseOverlays("second", second, first)...)
+	return reading, nil
+}
+
+// houseOverlays owner一方的天体落在另一方星盘中的宫位
+func houseOverlays(owner string, chart, other *models.NatalChart) []models.HouseOverlay {
+	cusps := other.Houses.Placidus
+	if cusps == nil {
+		cusps = other.Houses.WholeSign
+	}
+	var houses [12]float64
+	for i, cusp := range cusps {
+		houses[i] = cusp.Longitude
+	}
+	overlays := make([]models.HouseOverlay, 0, len(chart.Planets))
+	for _, p := range chart.Planets {
+		overlays = append(overlays, models.HouseOverlay{
+			Owner: owner,
+			Body:  p.Body,
+			House: astro.HouseOf(p.Longitude, houses),
+		})
+	}
+	return overlays
+}
+
+// describeZodiac 将星座占卜结果整理为供AI解析引用的文字
+func describeZodiac(reading *models.ZodiacReading) string {
+	var b strings.Builder
+	b.WriteString(describeNatal(reading.Natal))
+	fmt.Fprintf(&b, "占卜时刻：%s\n", reading.Time)
+	if len(reading.Transits) == 0 {
+		b.WriteString("此刻没有容许度一度以内的行运相位\n")
+		return b.String()
+	}
+	parts := make([]string, 0, len(reading.Transits))
+	for _, a := range reading.Transits {
+		parts = append(parts, aspectText(a, "行运", "本命"))
+	}
+	fmt.Fprintf(&b, "行运相位：%s\n", strings.Join(parts, "；"))
+	return b.String()
+}
+
+// describeSynastry 将合盘结果整理为供AI解析引用的文字
+func describeSynastry(reading *models.SynastryReading) string {
+	var b strings.Builder
+	b.WriteString("【第一人】\n")
+	b.WriteString(describeNatal(reading.First))
+	b.WriteString("【第二人】\n")
+	b.WriteString(describeNatal(reading.Second))
+	parts := make([]string, 0, len(reading.Aspects))
+	for _, a := range reading.Aspects {
+		parts = append(parts, aspectText(a, "甲方", "乙方"))
+	}
+	fmt.Fprintf(&b, "合盘相位（甲方为第一人，乙方为第二人）：%s\n", strings.Join(parts, "；"))
+	labels := []struct{ Owner, Text string }{
+		{"first", "甲方天体落入乙方宫位"},
+		{"second", "乙方天体落入甲方宫位"},
+	}
+	for _, label := range labels {
+		var overlays []string
+		for _, o := range reading.Overlays {
+			if o.Owner == label.Owner {
+				overlays = append(overlays, fmt.Sprintf("%s第%d宫", o.Body.Name(), o.House))
+			}
+		}
+		fmt.Fprintf(&b, "%s：%s\n", label.Text, strings.Join(overlays, "，"))
+	}
+	return b.String()
+}
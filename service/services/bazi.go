@@ -0,0 +1,341 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/hobbyqhd/yijing/service/calendar"
+	"github.com/hobbyqhd/yijing/service/config"
+	"github.com/hobbyqhd/yijing/service/geo"
+	"github.com/hobbyqhd/yijing/service/models"
+)
+
+// baziTimeLayout 出生时间格式
+const baziTimeLayout = "2006-01-02 15:04:05"
+
+// defaultTimeZone 未指定时区与出生地时按北京时间解析
+const defaultTimeZone = "Asia/Shanghai"
+
+// BaziInput 八字排盘的输入参数，也可直接以出生时间字符串作为输入
+type BaziInput struct {
+	BirthTime  string            `json:"birthTime"`            // 出生时间（出生地钟表时间），格式为2006-01-02 15:04:05
+	Lunar      bool              `json:"lunar,omitempty"`      // 出生时间的日期部分是否为农历
+	LeapMonth  bool              `json:"leapMonth,omitempty"`  // 农历出生月是否为闰月
+	ZiHourRule models.ZiHourRule `json:"ziHourRule,omitempty"` // 子时换日规则，默认取环境变量BAZI_ZI_HOUR_RULE，未配置时23时换日
+	TimeZone   string            `json:"timeZone,omitempty"`   // IANA时区名，默认取出生城市所在时区；只给经纬度时必填；都不给时为Asia/Shanghai
+	DST        *bool             `json:"dst,omitempty"`        // 钟表时间是否为夏令时，不填时按时区数据库判断
+	City       string            `json:"city,omitempty"`       // 出生地城市名（中文或拼音），用于查询经纬度
+	Longitude  *float64          `json:"longitude,omitempty"`  // 出生地经度，东经为正，优先于城市
+	Latitude   *float64          `json:"latitude,omitempty"`   // 出生地纬度，北纬为正，优先于城市
+	Gender     models.Gender     `json:"gender,omitempty"`     // 性别：male或female，提供时排大运
+	AnnualFrom int               `json:"annualFrom,omitempty"` // 流年起始公历年，默认为今年
+	AnnualTo   int               `json:"annualTo,omitempty"`   // 流年结束公历年，默认为起始年后九年
+	Symbols    bool              `json:"symbols,omitempty"`    // 是否附纳音、十二长生与神煞
+	ShenSha    []string          `json:"shenSha,omitempty"`    // 只查所列神煞，默认查起例表中的全部
+}
+
+// maxAnnualYears 一次最多排出的流年数
+const maxAnnualYears = 120
+
+// baziBirth 解析后的出生信息
+type baziBirth struct {
+	Time       time.Time          // 排盘所用时间，提供出生地时为当地真太阳时
+	Lunar      calendar.LunarDate // 农历生日（按出生地日期）
+	ZiHourRule models.ZiHourRule  // 子时换日规则
+	SolarTime  *models.SolarTime  // 真太阳时校正记录
+	Gender     models.Gender      // 性别
+	AnnualFrom int                // 流年起始公历年
+	AnnualTo   int                // 流年结束公历年
+	Symbols    bool               // 是否附纳音、十二长生与神煞
+	ShenSha    []shenShaRule      // 所查神煞的起例
+}
+
+// parseBaziInput 解析八字排盘输入：按时区（及夏令时标记）解析钟表时间，
+// 提供出生地经度时换算为真太阳时
+func parseBaziInput(input interface{}) (*baziBirth, error) {
+	params := &BaziInput{}
+	if birthTime, ok := input.(string); ok {
+		params.BirthTime = birthTime
+	} else if err := decodeInput(input, params); err != nil {
+		return nil, &InputError{fmt.Errorf("八字排盘参数格式错误")}
+	}
+	if params.BirthTime == "" {
+		return nil, &InputError{fmt.Errorf("八字占卜需要提供出生时间")}
+	}
+	if params.Lunar {
+		solar, err := lunarBirthTime(params.BirthTime, params.LeapMonth)
+		if err != nil {
+			return nil, &InputError{err}
+		}
+		params.BirthTime = solar
+	}
+
+	birth := &baziBirth{ZiHourRule: params.ZiHourRule}
+	if birth.ZiHourRule == "" {
+		birth.ZiHourRule = models.ZiHourRule(config.GetEnv("BAZI_ZI_HOUR_RULE"))
+	}
+	switch birth.ZiHourRule {
+	case "":
+		birth.ZiHourRule = models.ZiHourNextDay
+	case models.ZiHourNextDay, models.ZiHourSameDay:
+	default:
+		return nil, &InputError{fmt.Errorf("不支持的子时换日规则: %s", birth.ZiHourRule)}
+	}
+
+	switch params.Gender {
+	case "", models.Male, models.Female:
+		birth.Gender = params.Gender
+	default:
+		return nil, &InputError{fmt.Errorf("性别应为male或female")}
+	}
+
+	birth.AnnualFrom, birth.AnnualTo = params.AnnualFrom, params.AnnualTo
+	if birth.AnnualFrom == 0 {
+		birth.AnnualFrom = time.Now().Year()
+	}
+	if birth.AnnualTo == 0 {
+		birth.AnnualTo = birth.AnnualFrom + 9
+	}
+	if birth.AnnualTo < birth.AnnualFrom || birth.AnnualTo-birth.AnnualFrom >= maxAnnualYears {
+		return nil, &InputError{fmt.Errorf("流年范围有误，最多排%d年", maxAnnualYears)}
+	}
+
+	if params.Symbols {
+		rules, err := lookupShenShaRules(params.ShenSha)
+		if err != nil {
+			return nil, &InputError{err}
+		}
+		birth.Symbols, birth.ShenSha = true, rules
+	}
+
+	// 确定出生地
+	place, err := resolveBirthPlace(params.City, params.Longitude, params.Latitude, params.TimeZone)
+	if err != nil {
+		return nil, &InputError{err}
+	}
+	clock, dst, err := parseClockTime(params.BirthTime, place.TimeZone, params.DST)
+	if err != nil {
+		return nil, &InputError{err}
+	}
+	longitude, latitude := place.Longitude, place.Latitude
+
+	birth.Time = clock
+	birth.Lunar = calendar.LunarOfDate(clock.Date())
+	if longitude == nil {
+		return birth, nil
+	}
+
+	apparent, eot := calendar.ApparentSolarTime(clock, *longitude)
+	// 以两者的钟面读数之差作为校正量
+	given, _ := time.Parse(baziTimeLayout, params.BirthTime)
+	corrected, _ := time.Parse(baziTimeLayout, apparent.Format(baziTimeLayout))
+	birth.Time = apparent
+	birth.SolarTime = &models.SolarTime{
+		ClockTime:      params.BirthTime,
+		TimeZone:       place.TimeZone,
+		DST:            dst,
+		Place:          place.Name,
+		Longitude:      *longitude,
+		EquationOfTime: math.Round(eot*100) / 100,
+		Correction:     math.Round(corrected.Sub(given).Minutes()*100) / 100,
+		ApparentTime:   apparent.Format(baziTimeLayout),
+	}
+	if latitude != nil {
+		birth.SolarTime.Latitude = *latitude
+	}
+	return birth, nil
+}
+
+// birthPlace 出生地的名称、经纬度与时区
+type birthPlace struct {
+	Name      string
+	Longitude *float64 // 东经为正，未提供时为nil
+	Latitude  *float64 // 北纬为正，未提供时为nil
+	TimeZone  string   // IANA时区名
+}
+
+// resolveBirthPlace 确定出生地：按城市查经纬度与时区，直接给出的经纬度逐项优先于城市。
+// 时区默认取出生地所在时区；只给经纬度不给城市时须指明时区，都不给时按Asia/Shanghai
+func resolveBirthPlace(cityName string, longitude, latitude *float64, timeZone string) (*birthPlace, error) {
+	place := &birthPlace{TimeZone: timeZone}
+	if cityName != "" {
+		city, err := geo.Lookup(cityName)
+		if errors.Is(err, geo.ErrCityNotFound) {
+			return nil, fmt.Errorf("未找到出生地: %s", cityName)
+		}
+		if err != nil {
+			return nil, err
+		}
+		place.Name, place.Longitude, place.Latitude = city.Name, &city.Longitude, &city.Latitude
+		if place.TimeZone == "" {
+			place.TimeZone = city.TimeZone
+		}
+	}
+	if (longitude != nil && math.Abs(*longitude) > 180) || (latitude != nil && math.Abs(*latitude) > 90) {
+		return nil, fmt.Errorf("出生地经纬度超出范围")
+	}
+	if longitude != nil {
+		place.Longitude = longitude
+	}
+	if latitude != nil {
+		place.Latitude = latitude
+	}
+	if place.TimeZone == "" {
+		// 经度无从推断时区，不能默认按北京时间解析海外的出生时间
+		if longitude != nil {
+			return nil, fmt.Errorf("只给出经纬度时需同时给出时区timeZone，如Asia/Shanghai")
+		}
+		place.TimeZone = defaultTimeZone
+	}
+	return place, nil
+}
+
+// parseClockTime 按时区解析出生地钟表时间。用户明确说明是否为夏令时且与时区数据库不一致时，
+// 以用户为准。返回的第二个值为是否夏令时
+func parseClockTime(value, timeZone string, dst *bool) (time.Time, bool, error) {
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("无法识别的时区: %s", timeZone)
+	}
+	clock, err := time.ParseInLocation(baziTimeLayout, value, location)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("出生时间格式错误")
+	}
+	isDST := clock.IsDST()
+	if dst != nil && *dst != isDST {
+		if *dst {
+			clock = clock.Add(-time.Hour)
+		} else {
+			clock = clock.Add(time.Hour)
+		}
+		isDST = *dst
+	}
+	return clock, isDST, nil
+}
+
+// lunarBirthTime 将日期部分为农历的出生时间换算为公历，时刻部分不变
+func lunarBirthTime(birthTime string, leap bool) (string, error) {
+	var year, month, day, hour, minute, second int
+	if _, err := fmt.Sscanf(birthTime, "%d-%d-%d %d:%d:%d", &year, &month, &day, &hour, &minute, &second); err != nil {
+		return "", fmt.Errorf("出生时间格式错误")
+	}
+	date, err := calendar.LunarToSolar(calendar.LunarDate{Year: year, Month: month, Day: day, IsLeap: leap})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %02d:%02d:%02d", date.Format("2006-01-02"), hour, minute, second), nil
+}
+
+// dayIndex 日柱在六十甲子中的序数。23时起的子时按规则决定是否归入次日
+func dayIndex(birthTime time.Time, rule models.ZiHourRule) int {
+	index := calendar.SexagenaryDay(birthTime.Year(), birthTime.Month(), birthTime.Day())
+	if birthTime.Hour() == 23 && rule != models.ZiHourSameDay {
+		index = (index + 1) % 60
+	}
+	return index
+}
+
+// describeBazi 将命盘整理为供AI解析引用的文字
+func describeBazi(reading *models.BaziReading) string {
+	var b strings.Builder
+	chart := reading.Chart
+	fmt.Fprintf(&b, "四柱：%s%s年 %s%s月 %s%s日 %s%s时\n",
+		chart.Year.Stem, chart.Year.Branch, chart.Month.Stem, chart.Month.Branch,
+		chart.Day.Stem, chart.Day.Branch, chart.Hour.Stem, chart.Hour.Branch)
+	fmt.Fprintf(&b, "农历生日：%s\n", reading.LunarBirth)
+	fmt.Fprintf(&b, "日主：%s%s\n", reading.DayMaster, reading.DayMaster.Element())
+
+	names := [4]string{"年柱", "月柱", "日柱", "时柱"}
+	details := [4]models.PillarDetail{reading.Details.Year, reading.Details.Month, reading.Details.Day, reading.Details.Hour}
+	for i, detail := range details {
+		hidden := make([]string, 0, len(detail.HiddenStems))
+		for _, h := range detail.HiddenStems {
+			hidden = append(hidden, fmt.Sprintf("%s（%s）", h.Stem, h.TenGod))
+		}
+		fmt.Fprintf(&b, "%s：天干%s，藏干%s\n", names[i], detail.TenGod, strings.Join(hidden, "、"))
+	}
+
+	parts := make([]string, 0, len(reading.ElementScores))
+	for _, score := range reading.ElementScores {
+		parts = append(parts, fmt.Sprintf("%s%.1f%%（%s）", score.Element, score.Percent, score.Season))
+	}
+	fmt.Fprintf(&b, "五行力量：%s\n", strings.Join(parts, "，"))
+	fmt.Fprintf(&b, "旺衰与格局：%s\n", reading.Luck)
+	fmt.Fprintf(&b, "推断过程：%s\n", strings.Join(reading.Analysis.Trail, "；"))
+	if symbols := reading.Symbols; symbols != nil {
+		fmt.Fprintf(&b, "纳音：%s、%s、%s、%s；日主十二长生：%s、%s、%s、%s\n",
+			symbols.Year.NaYin, symbols.Month.NaYin, symbols.Day.NaYin, symbols.Hour.NaYin,
+			symbols.Year.LifeStage, symbols.Month.LifeStage, symbols.Day.LifeStage, symbols.Hour.LifeStage)
+		if len(symbols.ShenSha) > 0 {
+			stars := make([]string, 0, len(symbols.ShenSha))
+			for _, star := range symbols.ShenSha {
+				stars = append(stars, fmt.Sprintf("%s（%s柱%s）", star.Name, positionNames[star.Pillar], star.Branch))
+			}
+			fmt.Fprintf(&b, "神煞：%s\n", strings.Join(stars, "，"))
+		}
+	}
+	if len(reading.Interactions) > 0 {
+		fmt.Fprintf(&b, "原局干支作用：%s\n", describeInteractions(reading.Interactions))
+	}
+	if reading.SolarTime != nil {
+		fmt.Fprintf(&b, "出生时间已按真太阳时校正为%s\n", reading.SolarTime.ApparentTime)
+	}
+	if cycle := reading.LuckCycle; cycle != nil {
+		luck := make([]string, 0, len(cycle.Pillars))
+		for _, p := range cycle.Pillars {
+			luck = append(luck, fmt.Sprintf("%d年%s%s（%s%s）", p.StartYear, p.Stem, p.Branch, p.TenGod, withInteractions(p.Interactions)))
+		}
+		fmt.Fprintf(&b, "大运：%d岁%d个月起运，%s\n", cycle.StartAge.Years, cycle.StartAge.Months, strings.Join(luck, "，"))
+	}
+	if len(reading.AnnualPillars) > 0 {
+		annual := make([]string, 0, len(reading.AnnualPillars))
+		for _, p := range reading.AnnualPillars {
+			annual = append(annual, fmt.Sprintf("%d年%s%s（%s%s）", p.Year, p.Stem, p.Branch, p.TenGod, withInteractions(p.Interactions)))
+		}
+		fmt.Fprintf(&b, "流年：%s\n", strings.Join(annual, "，"))
+	}
+	return b.String()
+}
+
+// positionNames 柱位的中文名
+var positionNames = map[models.PillarPosition]string{
+	models.PositionYear:   "年",
+	models.PositionMonth:  "月",
+	models.PositionDay:    "日",
+	models.PositionHour:   "时",
+	models.PositionLuck:   "运",
+	models.PositionAnnual: "岁",
+}
+
+// describeInteractions 将干支作用整理为“年月子丑六合化土”一类的短语
+func describeInteractions(items []models.Interaction) string {
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		var b strings.Builder
+		for _, p := range item.Pillars {
+			b.WriteString(positionNames[p])
+		}
+		b.WriteString(strings.Join(item.Members, ""))
+		b.WriteString(string(item.Kind))
+		switch {
+		case item.Element == "":
+		case item.Kind == models.StemCombination || item.Kind == models.SixCombination:
+			fmt.Fprintf(&b, "化%s", item.Element)
+		default:
+			fmt.Fprintf(&b, "成%s局", item.Element)
+		}
+		parts = append(parts, b.String())
+	}
+	return strings.Join(parts, "，")
+}
+
+// withInteractions 岁运与原局的作用，附在岁运说明之后
+func withInteractions(items []models.Interaction) string {
+	if len(items) == 0 {
+		return ""
+	}
+	return "；" + describeInteractions(items)
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hobbyqhd/yijing/service/models"
+)
+
+// 日主旺衰的判定阈值，以比劫与印星占五行总分的百分比计
+const (
+	strongSupport       = 55.0 // 不低于此为身强
+	weakSupport         = 45.0 // 不高于此为身弱
+	followWeakSupport   = 20.0 // 低于此且无根无助为从弱
+	followStrongSupport = 80.0 // 高于此且官杀无力为从强
+	feebleOfficer       = 5.0  // 官杀占比低于此视为无力
+	dominantShare       = 40.0 // 从弱时某一五行占比达此方论从财、从杀、从儿
+	exclusiveShare      = 60.0 // 从强时日主五行占比达此为专旺
+	usefulOfficerShare  = 10.0 // 身强时官杀占比达此方取官杀为用
+)
+
+// elementRelations 以日主五行为“我”的五类五行
+type elementRelations struct {
+	Self     models.Element // 比劫
+	Output   models.Element // 食伤（我生）
+	Wealth   models.Element // 财星（我克）
+	Officer  models.Element // 官杀（克我）
+	Resource models.Element // 印星（生我）
+}
+
+// relationsOf 求日主五行的生克关系
+func relationsOf(self models.Element) elementRelations {
+	r := elementRelations{Self: self, Output: self.Generates(), Wealth: self.Overcomes()}
+	for _, e := range allElements {
+		if e.Overcomes() == self {
+			r.Officer = e
+		}
+		if e.Generates() == self {
+			r.Resource = e
+		}
+	}
+	return r
+}
+
+// exclusivePatterns 专旺格按日主五行命名
+var exclusivePatterns = map[models.Element]string{
+	models.Wood:  "曲直格",
+	models.Fire:  "炎上格",
+	models.Earth: "稼穑格",
+	models.Metal: "从革格",
+	models.Water: "润下格",
+}
+
+// monthPatterns 以月令所取十神命名的正格
+var monthPatterns = map[models.TenGod]string{
+	models.DirectOfficer:    "正官格",
+	models.SevenKillings:    "七杀格",
+	models.DirectWealth:     "正财格",
+	models.IndirectWealth:   "偏财格",
+	models.EatingGod:        "食神格",
+	models.HurtingOfficer:   "伤官格",
+	models.DirectResource:   "正印格",
+	models.IndirectResource: "偏印格",
+	models.Companion:        "建禄格",
+	models.RobWealth:        "月刃格",
+}
+
+// analyzeBazi 以扶抑为主推定日主旺衰、格局与用神：
+// 先据五行得分、月令、通根与透干定旺衰，极端者论从；正格以月令透干取格
+func analyzeBazi(chart models.BaziChart, scores []models.ElementScore) models.BaziAnalysis {
+	dayMaster := chart.Day.Stem
+	rel := relationsOf(dayMaster.Element())
+	percent := make(map[models.Element]float64, len(scores))
+	for _, s := range scores {
+		percent[s.Element] = s.Percent
+	}
+
+	analysis := models.BaziAnalysis{Trail: make([]string, 0, 8)}
+	trail := func(format string, args ...interface{}) {
+		analysis.Trail = append(analysis.Trail, fmt.Sprintf(format, args...))
+	}
+
+	// 得令、得地、得势
+	strength := &analysis.Strength
+	strength.Season = seasonState(rel.Self, chart.Month.Branch.Element())
+	strength.InSeason = strength.Season == "旺" || strength.Season == "相"
+	strength.Support = roundTo(percent[rel.Self]+percent[rel.Resource], 1)
+	trail("日主%s%s生于%s月，在月令为%s，%s", dayMaster, rel.Self, chart.Month.Branch, strength.Season, map[bool]string{true: "得令", false: "失令"}[strength.InSeason])
+
+	rootWeight := 0.0
+	strength.Roots = make([]string, 0)
+	names := [4]string{"年", "月", "日", "时"}
+	pillars := [4]models.BaziPillar{chart.Year, chart.Month, chart.Day, chart.Hour}
+	for i, pillar := range pillars {
+		for j, entry := range hiddenStemTable[pillar.Branch] {
+			if entry.Stem.Element() == rel.Self {
+				rootWeight += entry.Weight
+				strength.Roots = append(strength.Roots, fmt.Sprintf("%s支%s（%s%s）", names[i], pillar.Branch, entry.Stem, hiddenStemRoles[j]))
+			}
+		}
+	}
+	if len(strength.Roots) > 0 {
+		trail("通根于%s，得地", strings.Join(strength.Roots, "、"))
+	} else {
+		trail("地支无比劫之根，失地")
+	}
+
+	strength.Helpers = make([]string, 0)
+	for i, pillar := range pillars {
+		if i == 2 {
+			continue
+		}
+		if e := pillar.Stem.Element(); e == rel.Self || e == rel.Resource {
+			strength.Helpers = append(strength.Helpers, fmt.Sprintf("%s干%s（%s）", names[i], pillar.Stem, tenGod(dayMaster, pillar.Stem)))
+		}
+	}
+	if len(strength.Helpers) > 0 {
+		trail("天干透出%s相助，得势", strings.Join(strength.Helpers, "、"))
+	} else {
+		trail("天干无比劫、印星相助，失势")
+	}
+	trail("比劫%s占%.1f%%，印星%s占%.1f%%，合计%.1f%%", rel.Self, percent[rel.Self], rel.Resource, percent[rel.Resource], strength.Support)
+
+	opposing := []models.Element{rel.Output, rel.Wealth, rel.Officer}
+	sort.SliceStable(opposing, func(i, j int) bool { return percent[opposing[i]] > percent[opposing[j]] })
+	dominant := opposing[0]
+
+	favorable := &analysis.Favorable
+	pattern := &analysis.Pattern
+	switch {
+	case strength.Support < followWeakSupport && rootWeight < 0.3 && len(strength.Helpers) == 0:
+		strength.Level = models.FollowWeak
+		trail("同党不足%.0f%%，无根无助，日主弃命相从", followWeakSupport)
+		pattern.Special = true
+		switch {
+		case percent[dominant] < dominantShare:
+			pattern.Name = "从势格"
+		case dominant == rel.Wealth:
+			pattern.Name = "从财格"
+		case dominant == rel.Officer:
+			pattern.Name = "从杀格"
+		default:
+			pattern.Name = "从儿格"
+		}
+		pattern.Basis = fmt.Sprintf("%s最旺，占%.1f%%", dominant, percent[dominant])
+		favorable.Useful = dominant
+		favorable.Favorable = opposing[1:]
+		favorable.Unfavorable = []models.Element{rel.Resource, rel.Self}
+		trail("从其旺神，以%s为用，忌比劫、印星扶身", dominant)
+
+	case strength.Support > followStrongSupport && percent[rel.Officer] < feebleOfficer:
+		strength.Level = models.FollowStrong
+		trail("同党逾%.0f%%，官杀无力，日主旺极宜从", followStrongSupport)
+		pattern.Special = true
+		if percent[rel.Self] >= exclusiveShare {
+			pattern.Name = exclusivePatterns[rel.Self]
+			pattern.Basis = fmt.Sprintf("%s气专旺，占%.1f%%", rel.Self, percent[rel.Self])
+			favorable.Useful = rel.Self
+			favorable.Favorable = []models.Element{rel.Resource, rel.Output}
+		} else {
+			pattern.Name = "从强格"
+			pattern.Basis = fmt.Sprintf("比劫、印星合占%.1f%%", strength.Support)
+			favorable.Useful = rel.Resource
+			favorable.Favorable = []models.Element{rel.Self}
+		}
+		favorable.Unfavorable = []models.Element{rel.Officer, rel.Wealth}
+		trail("顺其旺势，以%s为用，忌官杀、财星逆其气", favorable.Useful)
+
+	default:
+		switch {
+		case strength.Support >= strongSupport:
+			strength.Level = models.StrengthStrong
+		case strength.Support <= weakSupport:
+			strength.Level = models.StrengthWeak
+		default:
+			strength.Level = models.StrengthBalanced
+		}
+		strong := strength.Support >= (strongSupport+weakSupport)/2
+		if strength.Level == models.StrengthBalanced {
+			trail("同党在%.0f%%至%.0f%%之间，日主中和%s", weakSupport, strongSupport, map[bool]string{true: "偏强", false: "偏弱"}[strong])
+		} else {
+			trail("日主%s", strength.Level)
+		}
+
+		pattern.Name, pattern.Basis = ordinaryPattern(chart)
+		trail("%s，取%s", pattern.Basis, pattern.Name)
+
+		if strong {
+			// 身强宜克泄耗：印多用财破印，比劫多有官杀则用官杀，否则用食伤泄秀
+			switch {
+			case percent[rel.Resource] > percent[rel.Self]:
+				favorable.Useful = rel.Wealth
+				trail("印星过重，以财星%s破印为用", rel.Wealth)
+			case percent[rel.Officer] >= usefulOfficerShare:
+				favorable.Useful = rel.Officer
+				trail("比劫当权，官杀有力，以官杀%s制身为用", rel.Officer)
+			default:
+				favorable.Useful = rel.Output
+				trail("比劫当权，官杀无力，以食伤%s泄秀为用", rel.Output)
+			}
+			for _, e := range opposing {
+				if e != favorable.Useful {
+					favorable.Favorable = append(favorable.Favorable, e)
+				}
+			}
+			favorable.Unfavorable = byPercent(percent, rel.Resource, rel.Self)
+		} else {
+			// 身弱宜生扶：官杀、食伤重者用印化之，财重者用比劫分之
+			if dominant == rel.Wealth {
+				favorable.Useful = rel.Self
+				favorable.Favorable = []models.Element{rel.Resource}
+				trail("财星%s最重，以比劫%s帮身任财为用", rel.Wealth, rel.Self)
+			} else {
+				favorable.Useful = rel.Resource
+				favorable.Favorable = []models.Element{rel.Self}
+				trail("%s最重，以印星%s%s为用", dominant, rel.Resource, map[bool]string{true: "化杀生身", false: "制伤生身"}[dominant == rel.Officer])
+			}
+			favorable.Unfavorable = opposing[:2]
+		}
+	}
+	trail("用神%s，喜神%s，忌神%s", favorable.Useful, joinElements(favorable.Favorable), joinElements(favorable.Unfavorable))
+	return analysis
+}
+
+// ordinaryPattern 正格以月令取格：月支藏干透出年、月、时干者依本气、中气、余气之序取之，
+// 比劫透出不论；无透干时以本气定格
+func ordinaryPattern(chart models.BaziChart) (string, string) {
+	dayMaster := chart.Day.Stem
+	month := chart.Month.Branch
+	entries := hiddenStemTable[month]
+	for i, entry := range entries {
+		god := tenGod(dayMaster, entry.Stem)
+		if god == models.Companion || god == models.RobWealth {
+			continue
+		}
+		for _, stem := range [3]models.Stem{chart.Year.Stem, chart.Month.Stem, chart.Hour.Stem} {
+			if stem == entry.Stem {
+				return monthPatterns[god], fmt.Sprintf("月令%s%s%s透出天干，为%s", month, hiddenStemRoles[i], entry.Stem, god)
+			}
+		}
+	}
+	god := tenGod(dayMaster, entries[0].Stem)
+	return monthPatterns[god], fmt.Sprintf("月令%s本气%s为%s", month, entries[0].Stem, god)
+}
+
+// byPercent 将五行按占比由高到低排列
+func byPercent(percent map[models.Element]float64, elements ...models.Element) []models.Element {
+	sort.SliceStable(elements, func(i, j int) bool { return percent[elements[i]] > percent[elements[j]] })
+	return elements
+}
+
+// joinElements 以顿号连接五行
+func joinElements(elements []models.Element) string {
+	parts := make([]string, 0, len(elements))
+	for _, e := range elements {
+		parts = append(parts, string(e))
+	}
+	return strings.Join(parts, "、")
+}
+
+// luckSummary 旺衰、格局与喜忌的一句话结论，填入BaziReading.Luck
+func luckSummary(dayMaster models.Stem, analysis models.BaziAnalysis) string {
+	return fmt.Sprintf("日主%s%s%s（同党占%.1f%%），%s；用神%s，喜%s，忌%s",
+		dayMaster, dayMaster.Element(), analysis.Strength.Level, analysis.Strength.Support,
+		analysis.Pattern.Name, analysis.Favorable.Useful,
+		joinElements(analysis.Favorable.Favorable), joinElements(analysis.Favorable.Unfavorable))
+}
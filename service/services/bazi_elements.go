@@ -0,0 +1,174 @@
+package services
+
+import (
+	"math"
+	"sort"
+
+	"github.com/hobbyqhd/yijing/service/models"
+)
+
+// hiddenStemEntry 藏干及其权重
+type hiddenStemEntry struct {
+	Stem   models.Stem
+	Weight float64
+}
+
+// hiddenStemTable 地支藏干，依次为本气、中气、余气，权重按人元司令日数折算
+var hiddenStemTable = map[models.Branch][]hiddenStemEntry{
+	models.Zi:   {{models.Gui, 1.0}},
+	models.Chou: {{models.Ji, 0.6}, {models.Gui, 0.3}, {models.Xin, 0.1}},
+	models.Yin:  {{models.Jia, 0.6}, {models.Bing, 0.3}, {models.Wu4, 0.1}},
+	models.Mao:  {{models.Yi, 1.0}},
+	models.Chen: {{models.Wu4, 0.6}, {models.Yi, 0.3}, {models.Gui, 0.1}},
+	models.Si:   {{models.Bing, 0.6}, {models.Geng, 0.3}, {models.Wu4, 0.1}},
+	models.Wu:   {{models.Ding, 0.7}, {models.Ji, 0.3}},
+	models.Wei:  {{models.Ji, 0.6}, {models.Ding, 0.3}, {models.Yi, 0.1}},
+	models.Shen: {{models.Geng, 0.6}, {models.Ren, 0.3}, {models.Wu4, 0.1}},
+	models.You:  {{models.Xin, 1.0}},
+	models.Xu:   {{models.Wu4, 0.6}, {models.Xin, 0.3}, {models.Ding, 0.1}},
+	models.Hai:  {{models.Ren, 0.7}, {models.Jia, 0.3}},
+}
+
+// hiddenStemRoles 藏干次序的名称
+var hiddenStemRoles = [3]string{"本气", "中气", "余气"}
+
+// seasonFactors 月令旺衰对五行力量的修正系数
+var seasonFactors = map[string]float64{
+	"旺": 1.5,
+	"相": 1.2,
+	"休": 1.0,
+	"囚": 0.8,
+	"死": 0.6,
+}
+
+// monthCommandWeight 月令（月支）为提纲，其藏干按此倍数计分
+const monthCommandWeight = 2.0
+
+// allElements 五行，按木火土金水排列
+var allElements = [5]models.Element{models.Wood, models.Fire, models.Earth, models.Metal, models.Water}
+
+// hiddenStems 求地支藏干及其相对日主的十神
+func hiddenStems(dayMaster models.Stem, branch models.Branch) []models.HiddenStem {
+	entries := hiddenStemTable[branch]
+	result := make([]models.HiddenStem, 0, len(entries))
+	for i, entry := range entries {
+		result = append(result, models.HiddenStem{
+			Stem:   entry.Stem,
+			Role:   hiddenStemRoles[i],
+			Weight: entry.Weight,
+			TenGod: tenGod(dayMaster, entry.Stem),
+		})
+	}
+	return result
+}
+
+// tenGod 以日主为“我”，按五行生克与阴阳异同定十神
+func tenGod(dayMaster, stem models.Stem) models.TenGod {
+	self, other := dayMaster.Element(), stem.Element()
+	same := dayMaster.IsYang() == stem.IsYang()
+	pick := func(sameGod, diffGod models.TenGod) models.TenGod {
+		if same {
+			return sameGod
+		}
+		return diffGod
+	}
+	switch {
+	case other == self:
+		return pick(models.Companion, models.RobWealth)
+	case self.Generates() == other:
+		return pick(models.EatingGod, models.HurtingOfficer)
+	case self.Overcomes() == other:
+		return pick(models.IndirectWealth, models.DirectWealth)
+	case other.Overcomes() == self:
+		return pick(models.SevenKillings, models.DirectOfficer)
+	default:
+		return pick(models.IndirectResource, models.DirectResource)
+	}
+}
+
+// baziDetails 求四柱天干十神与地支藏干
+func baziDetails(chart models.BaziChart) models.BaziDetails {
+	dayMaster := chart.Day.Stem
+	detail := func(pillar models.BaziPillar) models.PillarDetail {
+		return models.PillarDetail{
+			TenGod:      tenGod(dayMaster, pillar.Stem),
+			HiddenStems: hiddenStems(dayMaster, pillar.Branch),
+		}
+	}
+	details := models.BaziDetails{
+		Year:  detail(chart.Year),
+		Month: detail(chart.Month),
+		Day:   detail(chart.Day),
+		Hour:  detail(chart.Hour),
+	}
+	details.Day.TenGod = models.DayMasterSelf
+	return details
+}
+
+// seasonState 五行在月令中的旺衰：当令者旺，令生者相，生令者休，克令者囚，令克者死
+func seasonState(element, monthElement models.Element) string {
+	switch {
+	case element == monthElement:
+		return "旺"
+	case monthElement.Generates() == element:
+		return "相"
+	case element.Generates() == monthElement:
+		return "休"
+	case element.Overcomes() == monthElement:
+		return "囚"
+	default:
+		return "死"
+	}
+}
+
+// elementScores 五行加权得分：天干各计1分，地支按藏干权重计分（月支加倍），
+// 再按各五行在月令中的旺衰乘以修正系数
+func elementScores(chart models.BaziChart) []models.ElementScore {
+	raw := make(map[models.Element]float64)
+	pillars := []models.BaziPillar{chart.Year, chart.Month, chart.Day, chart.Hour}
+	for i, pillar := range pillars {
+		raw[pillar.Stem.Element()] += 1
+		weight := 1.0
+		if i == 1 {
+			weight = monthCommandWeight
+		}
+		for _, entry := range hiddenStemTable[pillar.Branch] {
+			raw[entry.Stem.Element()] += entry.Weight * weight
+		}
+	}
+
+	monthElement := chart.Month.Branch.Element()
+	scores := make([]models.ElementScore, 0, len(allElements))
+	total := 0.0
+	for _, element := range allElements {
+		season := seasonState(element, monthElement)
+		score := raw[element] * seasonFactors[season]
+		total += score
+		scores = append(scores, models.ElementScore{Element: element, Score: score, Season: season})
+	}
+	for i := range scores {
+		scores[i].Percent = roundTo(scores[i].Score/total*100, 1)
+		scores[i].Score = roundTo(scores[i].Score, 2)
+	}
+	return scores
+}
+
+// elementsByStrength 将五行按得分由强到弱排列
+func elementsByStrength(scores []models.ElementScore) []models.Element {
+	sorted := make([]models.ElementScore, len(scores))
+	copy(sorted, scores)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Score > sorted[j].Score
+	})
+	elements := make([]models.Element, 0, len(sorted))
+	for _, score := range sorted {
+		elements = append(elements, score.Element)
+	}
+	return elements
+}
+
+// roundTo 四舍五入到指定小数位
+func roundTo(value float64, digits int) float64 {
+	scale := math.Pow(10, float64(digits))
+	return math.Round(value*scale) / scale
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"github.com/hobbyqhd/yijing/service/models"
+)
+
+// placedPillar 参与分析的一柱及其位置
+type placedPillar struct {
+	Position models.PillarPosition
+	Pillar   models.BaziPillar
+}
+
+// pairRule 两两之间的作用
+type pairRule struct {
+	A, B    string
+	Element models.Element
+	Note    string
+}
+
+// groupRule 三支成局的作用
+type groupRule struct {
+	Members [3]models.Branch
+	Element models.Element
+	Note    string
+}
+
+// stemCombinations 天干五合及合化五行
+var stemCombinations = []pairRule{
+	{"甲", "己", models.Earth, "中正之合"},
+	{"乙", "庚", models.Metal, "仁义之合"},
+	{"丙", "辛", models.Water, "威制之合"},
+	{"丁", "壬", models.Wood, "淫慝之合"},
+	{"戊", "癸", models.Fire, "无情之合"},
+}
+
+// sixCombinations 地支六合及合化五行
+var sixCombinations = []pairRule{
+	{"子", "丑", models.Earth, ""},
+	{"寅", "亥", models.Wood, ""},
+	{"卯", "戌", models.Fire, ""},
+	{"辰", "酉", models.Metal, ""},
+	{"巳", "申", models.Water, ""},
+	{"午", "未", models.Earth, ""},
+}
+
+// clashes 地支六冲
+var clashes = []pairRule{
+	{A: "子", B: "午"}, {A: "丑", B: "未"}, {A: "寅", B: "申"},
+	{A: "卯", B: "酉"}, {A: "辰", B: "戌"}, {A: "巳", B: "亥"},
+}
+
+// harms 地支六害
+var harms = []pairRule{
+	{A: "子", B: "未"}, {A: "丑", B: "午"}, {A: "寅", B: "巳"},
+	{A: "卯", B: "辰"}, {A: "申", B: "亥"}, {A: "酉", B: "戌"},
+}
+
+// destructions 地支相破
+var destructions = []pairRule{
+	{A: "子", B: "酉"}, {A: "卯", B: "午"}, {A: "辰", B: "丑"},
+	{A: "未", B: "戌"}, {A: "寅", B: "亥"}, {A: "巳", B: "申"},
+}
+
+// mutualPunishments 两支相刑：寅巳申、丑戌未两两相刑，子卯相刑
+var mutualPunishments = []pairRule{
+	{A: "寅", B: "巳", Note: "无恩之刑"}, {A: "巳", B: "申", Note: "无恩之刑"}, {A: "申", B: "寅", Note: "无恩之刑"},
+	{A: "丑", B: "戌", Note: "恃势之刑"}, {A: "戌", B: "未", Note: "恃势之刑"}, {A: "未", B: "丑", Note: "恃势之刑"},
+	{A: "子", B: "卯", Note: "无礼之刑"},
+}
+
+// selfPunishments 自刑之支
+var selfPunishments = []models.Branch{models.Chen, models.Wu, models.You, models.Hai}
+
+// threeHarmonies 三合局，依次为长生、帝旺、墓库
+var threeHarmonies = []groupRule{
+	{[3]models.Branch{models.Shen, models.Zi, models.Chen}, models.Water, "申子辰合水局"},
+	{[3]models.Branch{models.Hai, models.Mao, models.Wei}, models.Wood, "亥卯未合木局"},
+	{[3]models.Branch{models.Yin, models.Wu, models.Xu}, models.Fire, "寅午戌合火局"},
+	{[3]models.Branch{models.Si, models.You, models.Chou}, models.Metal, "巳酉丑合金局"},
+}
+
+// directionalCombinations 三会方
+var directionalCombinations = []groupRule{
+	{[3]models.Branch{models.Yin, models.Mao, models.Chen}, models.Wood, "东方木"},
+	{[3]models.Branch{models.Si, models.Wu, models.Wei}, models.Fire, "南方火"},
+	{[3]models.Branch{models.Shen, models.You, models.Xu}, models.Metal, "西方金"},
+	{[3]models.Branch{models.Hai, models.Zi, models.Chou}, models.Water, "北方水"},
+}
+
+// threePunishments 三刑俱全
+var threePunishments = []groupRule{
+	{[3]models.Branch{models.Yin, models.Si, models.Shen}, "", "无恩之刑"},
+	{[3]models.Branch{models.Chou, models.Xu, models.Wei}, "", "恃势之刑"},
+}
+
+// chartPillars 原局四柱及其位置
+func chartPillars(chart models.BaziChart) []placedPillar {
+	return []placedPillar{
+		{models.PositionYear, chart.Year},
+		{models.PositionMonth, chart.Month},
+		{models.PositionDay, chart.Day},
+		{models.PositionHour, chart.Hour},
+	}
+}
+
+// chartInteractions 原局四柱之间的干支作用
+func chartInteractions(chart models.BaziChart) []models.Interaction {
+	return analyzeInteractions(chartPillars(chart), nil)
+}
+
+// pillarInteractions 大运或流年一柱与原局四柱之间的干支作用，只列出该柱参与的各项
+func pillarInteractions(chart models.BaziChart, position models.PillarPosition, stem models.Stem, branch models.Branch) []models.Interaction {
+	extra := placedPillar{position, models.BaziPillar{Stem: stem, Branch: branch}}
+	return analyzeInteractions(append(chartPillars(chart), extra), &extra)
+}
+
+// analyzeInteractions 找出诸柱之间的合、会、冲、刑、害、破。
+// focus不为空时只保留该柱参与的作用，用于分析岁运与原局的关系
+func analyzeInteractions(pillars []placedPillar, focus *placedPillar) []models.Interaction {
+	result := make([]models.Interaction, 0)
+	involves := func(positions []models.PillarPosition) bool {
+		if focus == nil {
+			return true
+		}
+		for _, p := range positions {
+			if p == focus.Position {
+				return true
+			}
+		}
+		return false
+	}
+	add := func(item models.Interaction) {
+		if involves(item.Pillars) {
+			result = append(result, item)
+		}
+	}
+
+	// 两两之间的作用
+	for i := 0; i < len(pillars); i++ {
+		for j := i + 1; j < len(pillars); j++ {
+			a, b := pillars[i], pillars[j]
+			positions := []models.PillarPosition{a.Position, b.Position}
+			stems := []string{string(a.Pillar.Stem), string(b.Pillar.Stem)}
+			branches := []string{string(a.Pillar.Branch), string(b.Pillar.Branch)}
+
+			if rule, ok := matchPair(stemCombinations, stems[0], stems[1]); ok {
+				add(models.Interaction{Kind: models.StemCombination, Pillars: positions, Members: stems, Element: rule.Element, Note: rule.Note})
+			}
+			if rule, ok := matchPair(sixCombinations, branches[0], branches[1]); ok {
+				add(models.Interaction{Kind: models.SixCombination, Pillars: positions, Members: branches, Element: rule.Element})
+			}
+			if rule, ok := halfHarmony(a.Pillar.Branch, b.Pillar.Branch); ok && !groupComplete(pillars, rule) {
+				add(models.Interaction{Kind: models.HalfHarmony, Pillars: positions, Members: branches, Element: rule.Element, Note: rule.Note})
+			}
+			if _, ok := matchPair(clashes, branches[0], branches[1]); ok {
+				add(models.Interaction{Kind: models.Clash, Pillars: positions, Members: branches})
+			}
+			if rule, ok := matchPair(mutualPunishments, branches[0], branches[1]); ok {
+				add(models.Interaction{Kind: models.MutualPunishment, Pillars: positions, Members: branches, Note: rule.Note})
+			}
+			if a.Pillar.Branch == b.Pillar.Branch && isSelfPunishment(a.Pillar.Branch) {
+				add(models.Interaction{Kind: models.SelfPunishment, Pillars: positions, Members: branches})
+			}
+			if _, ok := matchPair(harms, branches[0], branches[1]); ok {
+				add(models.Interaction{Kind: models.Harm, Pillars: positions, Members: branches})
+			}
+			if _, ok := matchPair(destructions, branches[0], branches[1]); ok {
+				add(models.Interaction{Kind: models.Destruction, Pillars: positions, Members: branches})
+			}
+		}
+	}
+
+	// 三支成局
+	groups := []struct {
+		kind  models.InteractionKind
+		rules []groupRule
+	}{
+		{models.ThreeHarmony, threeHarmonies},
+		{models.DirectionalCombination, directionalCombinations},
+		{models.ThreePunishment, threePunishments},
+	}
+	for _, group := range groups {
+		for _, rule := range group.rules {
+			if positions := groupPositions(pillars, rule); positions != nil {
+				members := make([]string, 0, 3)
+				for _, m := range rule.Members {
+					members = append(members, string(m))
+				}
+				add(models.Interaction{Kind: group.kind, Pillars: positions, Members: members, Element: rule.Element, Note: rule.Note})
+			}
+		}
+	}
+	return result
+}
+
+// matchPair 在规则表中查找两字（不论先后）构成的作用
+func matchPair(rules []pairRule, a, b string) (pairRule, bool) {
+	for _, rule := range rules {
+		if (rule.A == a && rule.B == b) || (rule.A == b && rule.B == a) {
+			return rule, true
+		}
+	}
+	return pairRule{}, false
+}
+
+// halfHarmony 半合：三合局中帝旺之支与长生或墓库之支相合
+func halfHarmony(a, b models.Branch) (groupRule, bool) {
+	for _, rule := range threeHarmonies {
+		middle := rule.Members[1]
+		for _, edge := range [2]models.Branch{rule.Members[0], rule.Members[2]} {
+			if (a == middle && b == edge) || (a == edge && b == middle) {
+				return groupRule{rule.Members, rule.Element, string(a) + string(b) + "半合" + string(rule.Element) + "局"}, true
+			}
+		}
+	}
+	return groupRule{}, false
+}
+
+// groupComplete 三支是否在诸柱中俱全，俱全时以三合论而不再论半合
+func groupComplete(pillars []placedPillar, rule groupRule) bool {
+	return groupPositions(pillars, rule) != nil
+}
+
+// groupPositions 三支俱全时各支所在的柱，不全时返回nil。
+// 同一支见于多柱时取最靠前的一柱，故原局已有之支不再归于岁运
+func groupPositions(pillars []placedPillar, rule groupRule) []models.PillarPosition {
+	positions := make([]models.PillarPosition, 0, 3)
+	for _, member := range rule.Members {
+		found := false
+		for _, p := range pillars {
+			if p.Pillar.Branch == member {
+				positions = append(positions, p.Position)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil
+		}
+	}
+	return positions
+}
+
+// isSelfPunishment 辰午酉亥见同支为自刑
+func isSelfPunishment(branch models.Branch) bool {
+	for _, b := range selfPunishments {
+		if b == branch {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"math"
+	"time"
+
+	"github.com/hobbyqhd/yijing/service/calendar"
+	"github.com/hobbyqhd/yijing/service/models"
+)
+
+// luckPillarCount 排出的大运步数
+const luckPillarCount = 10
+
+// luckCycle 排大运：阳年男、阴年女顺排，自月柱起顺推六十甲子；反之逆推。
+// 起运岁数以出生至下一节（顺排）或上一节至出生（逆排）的时长折算，三日为一岁
+func luckCycle(birthTime time.Time, gender models.Gender, chart models.BaziChart) *models.LuckCycle {
+	forward := chart.Year.Stem.IsYang() == (gender == models.Male)
+	prev, next := calendar.JieAround(birthTime)
+	jie := next
+	span := next.Time.Sub(birthTime)
+	if !forward {
+		jie = prev
+		span = birthTime.Sub(prev.Time)
+	}
+
+	// 一日折四个月，一个月按三十日计
+	months := span.Hours() / 24 * 4
+	age := models.AgeSpan{
+		Years:  int(months) / 12,
+		Months: int(months) % 12,
+		Days:   int(math.Round((months - math.Floor(months)) * 30)),
+	}
+	start := birthTime.AddDate(age.Years, age.Months, age.Days)
+
+	cycle := &models.LuckCycle{
+		Forward:   forward,
+		Jie:       jie.Name,
+		JieTime:   jie.Time.Format(baziTimeLayout),
+		StartAge:  age,
+		StartTime: start.Format(baziTimeLayout),
+		Pillars:   make([]models.LuckPillar, 0, luckPillarCount),
+	}
+	step := 1
+	if !forward {
+		step = -1
+	}
+	index := sexagenaryIndex(chart.Month.Stem, chart.Month.Branch)
+	for i := 1; i <= luckPillarCount; i++ {
+		n := ((index+step*i)%60 + 60) % 60
+		stem := models.Stems[n%10]
+		branch := models.Branches[n%12]
+		cycle.Pillars = append(cycle.Pillars, models.LuckPillar{
+			Stem:         stem,
+			Branch:       branch,
+			TenGod:       tenGod(chart.Day.Stem, stem),
+			Interactions: pillarInteractions(chart, models.PositionLuck, stem, branch),
+			StartAge:     age.Years + 10*(i-1),
+			StartYear:    start.Year() + 10*(i-1),
+			EndYear:      start.Year() + 10*i - 1,
+		})
+	}
+	return cycle
+}
+
+// annualPillars 排流年：公元4年为甲子年，逐年顺推
+func annualPillars(from, to, birthYear int, chart models.BaziChart) []models.AnnualPillar {
+	pillars := make([]models.AnnualPillar, 0, to-from+1)
+	for year := from; year <= to; year++ {
+		n := ((year-4)%60 + 60) % 60
+		stem := models.Stems[n%10]
+		branch := models.Branches[n%12]
+		pillars = append(pillars, models.AnnualPillar{
+			Year:         year,
+			Stem:         stem,
+			Branch:       branch,
+			TenGod:       tenGod(chart.Day.Stem, stem),
+			Age:          year - birthYear,
+			Interactions: pillarInteractions(chart, models.PositionAnnual, stem, branch),
+		})
+	}
+	return pillars
+}
+
+// sexagenaryIndex 干支在六十甲子中的序数（甲子为0）
+func sexagenaryIndex(stem models.Stem, branch models.Branch) int {
+	s, b := stem.Index(), branch.Index()
+	for n := s; n < 60; n += 10 {
+		if n%12 == b {
+			return n
+		}
+	}
+	return -1
+}
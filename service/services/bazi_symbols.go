@@ -0,0 +1,243 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hobbyqhd/yijing/service/iching"
+	"github.com/hobbyqhd/yijing/service/models"
+)
+
+// naYinNames 六十甲子纳音，每两柱同一纳音，自甲子、乙丑起
+var naYinNames = [30]string{
+	"海中金", "炉中火", "大林木", "路旁土", "剑锋金", "山头火",
+	"涧下水", "城头土", "白蜡金", "杨柳木", "泉中水", "屋上土",
+	"霹雳火", "松柏木", "长流水", "沙中金", "山下火", "平地木",
+	"壁上土", "金箔金", "覆灯火", "天河水", "大驿土", "钗钏金",
+	"桑柘木", "大溪水", "沙中土", "天上火", "石榴木", "大海水",
+}
+
+// lifeStages 十二长生，自长生起依次排列
+var lifeStages = [12]string{"长生", "沐浴", "冠带", "临官", "帝旺", "衰", "病", "死", "墓", "绝", "胎", "养"}
+
+// lifeStageOrigins 十天干长生之地：阳干顺行，阴干逆行
+var lifeStageOrigins = map[models.Stem]models.Branch{
+	models.Jia: models.Hai, models.Bing: models.Yin, models.Wu4: models.Yin, models.Geng: models.Si, models.Ren: models.Shen,
+	models.Yi: models.Wu, models.Ding: models.You, models.Ji: models.You, models.Xin: models.Zi, models.Gui: models.Mao,
+}
+
+// shenShaBasis 神煞的起例依据
+type shenShaBasis string
+
+const (
+	basisDayStem    shenShaBasis = "日干"
+	basisYearStem   shenShaBasis = "年干"
+	basisDayBranch  shenShaBasis = "日支"
+	basisYearBranch shenShaBasis = "年支"
+	basisDayPillar  shenShaBasis = "日柱" // 以日柱本身论，如魁罡
+	basisDayXun     shenShaBasis = "日旬" // 以日柱所在旬论，如空亡
+)
+
+// shenShaRule 神煞起例：以依据之干支查表，四柱地支见表中之支即为该神煞
+type shenShaRule struct {
+	Name  string
+	Bases []shenShaBasis
+	Table map[string]string // 依据之干支到所见地支；日柱起例时为成立的日柱
+	Verse string            // 口诀
+}
+
+// shenShaRules 常用神煞起例表，可按需增删
+var shenShaRules = []shenShaRule{
+	{
+		Name:  "天乙贵人",
+		Bases: []shenShaBasis{basisDayStem, basisYearStem},
+		Table: map[string]string{"甲": "丑未", "戊": "丑未", "庚": "丑未", "乙": "子申", "己": "子申",
+			"丙": "亥酉", "丁": "亥酉", "辛": "寅午", "壬": "卯巳", "癸": "卯巳"},
+		Verse: "甲戊庚牛羊，乙己鼠猴乡，丙丁猪鸡位，壬癸兔蛇藏，六辛逢马虎",
+	},
+	{
+		Name:  "太极贵人",
+		Bases: []shenShaBasis{basisDayStem, basisYearStem},
+		Table: map[string]string{"甲": "子午", "乙": "子午", "丙": "卯酉", "丁": "卯酉", "戊": "辰戌丑未",
+			"己": "辰戌丑未", "庚": "寅亥", "辛": "寅亥", "壬": "巳申", "癸": "巳申"},
+		Verse: "甲乙生人子午中，丙丁鸡兔定亨通，戊己两干临四季，庚辛寅亥禄丰隆，壬癸巳申偏喜美",
+	},
+	{
+		Name:  "文昌",
+		Bases: []shenShaBasis{basisDayStem, basisYearStem},
+		Table: map[string]string{"甲": "巳", "乙": "午", "丙": "申", "丁": "酉", "戊": "申",
+			"己": "酉", "庚": "亥", "辛": "子", "壬": "寅", "癸": "卯"},
+		Verse: "甲乙巳午报君知，丙戊申宫丁己鸡，庚猪辛鼠壬逢虎，癸人见卯入云梯",
+	},
+	{
+		Name:  "禄神",
+		Bases: []shenShaBasis{basisDayStem},
+		Table: map[string]string{"甲": "寅", "乙": "卯", "丙": "巳", "丁": "午", "戊": "巳",
+			"己": "午", "庚": "申", "辛": "酉", "壬": "亥", "癸": "子"},
+		Verse: "甲禄在寅，乙禄在卯，丙戊禄在巳，丁己禄在午，庚禄在申，辛禄在酉，壬禄在亥，癸禄在子",
+	},
+	{
+		Name:  "羊刃",
+		Bases: []shenShaBasis{basisDayStem},
+		Table: map[string]string{"甲": "卯", "丙": "午", "戊": "午", "庚": "酉", "壬": "子"},
+		Verse: "甲刃在卯，丙戊刃在午，庚刃在酉，壬刃在子（禄前一位）",
+	},
+	{
+		Name:  "桃花",
+		Bases: []shenShaBasis{basisYearBranch, basisDayBranch},
+		Table: map[string]string{"申": "酉", "子": "酉", "辰": "酉", "寅": "卯", "午": "卯", "戌": "卯",
+			"巳": "午", "酉": "午", "丑": "午", "亥": "子", "卯": "子", "未": "子"},
+		Verse: "申子辰在酉，寅午戌在卯，巳酉丑在午，亥卯未在子",
+	},
+	{
+		Name:  "驿马",
+		Bases: []shenShaBasis{basisYearBranch, basisDayBranch},
+		Table: map[string]string{"申": "寅", "子": "寅", "辰": "寅", "寅": "申", "午": "申", "戌": "申",
+			"巳": "亥", "酉": "亥", "丑": "亥", "亥": "巳", "卯": "巳", "未": "巳"},
+		Verse: "申子辰马在寅，寅午戌马在申，巳酉丑马在亥，亥卯未马在巳",
+	},
+	{
+		Name:  "华盖",
+		Bases: []shenShaBasis{basisYearBranch, basisDayBranch},
+		Table: map[string]string{"申": "辰", "子": "辰", "辰": "辰", "寅": "戌", "午": "戌", "戌": "戌",
+			"巳": "丑", "酉": "丑", "丑": "丑", "亥": "未", "卯": "未", "未": "未"},
+		Verse: "申子辰见辰，寅午戌见戌，巳酉丑见丑，亥卯未见未",
+	},
+	{
+		Name:  "将星",
+		Bases: []shenShaBasis{basisYearBranch, basisDayBranch},
+		Table: map[string]string{"申": "子", "子": "子", "辰": "子", "寅": "午", "午": "午", "戌": "午",
+			"巳": "酉", "酉": "酉", "丑": "酉", "亥": "卯", "卯": "卯", "未": "卯"},
+		Verse: "申子辰见子，寅午戌见午，巳酉丑见酉，亥卯未见卯",
+	},
+	{
+		Name:  "红鸾",
+		Bases: []shenShaBasis{basisYearBranch},
+		Table: map[string]string{"子": "卯", "丑": "寅", "寅": "丑", "卯": "子", "辰": "亥", "巳": "戌",
+			"午": "酉", "未": "申", "申": "未", "酉": "午", "戌": "巳", "亥": "辰"},
+		Verse: "卯上起子逆数之，数到当生年支是",
+	},
+	{
+		Name:  "魁罡",
+		Bases: []shenShaBasis{basisDayPillar},
+		Table: map[string]string{"庚辰": "", "庚戌": "", "壬辰": "", "戊戌": ""},
+		Verse: "日柱为庚辰、庚戌、壬辰、戊戌",
+	},
+	{
+		Name:  "空亡",
+		Bases: []shenShaBasis{basisDayXun},
+		Verse: "以日柱所在之旬，十干配十二支所余二支为空亡",
+	},
+}
+
+// naYin 干支的纳音
+func naYin(stem models.Stem, branch models.Branch) string {
+	return naYinNames[sexagenaryIndex(stem, branch)/2]
+}
+
+// lifeStage 天干在地支所处的十二长生之位
+func lifeStage(stem models.Stem, branch models.Branch) string {
+	steps := branch.Index() - lifeStageOrigins[stem].Index()
+	if !stem.IsYang() {
+		steps = -steps
+	}
+	return lifeStages[(steps%12+12)%12]
+}
+
+// lookupShenShaRules 按名称挑选神煞起例，names为空时取全部
+func lookupShenShaRules(names []string) ([]shenShaRule, error) {
+	if len(names) == 0 {
+		return shenShaRules, nil
+	}
+	rules := make([]shenShaRule, 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, rule := range shenShaRules {
+			if rule.Name == name {
+				rules = append(rules, rule)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("不支持的神煞: %s", name)
+		}
+	}
+	return rules, nil
+}
+
+// baziSymbols 求四柱纳音、日主十二长生与神煞
+func baziSymbols(chart models.BaziChart, rules []shenShaRule) *models.BaziSymbols {
+	dayMaster := chart.Day.Stem
+	symbol := func(pillar models.BaziPillar) models.PillarSymbols {
+		return models.PillarSymbols{
+			NaYin:     naYin(pillar.Stem, pillar.Branch),
+			LifeStage: lifeStage(dayMaster, pillar.Branch),
+		}
+	}
+	return &models.BaziSymbols{
+		Year:    symbol(chart.Year),
+		Month:   symbol(chart.Month),
+		Day:     symbol(chart.Day),
+		Hour:    symbol(chart.Hour),
+		ShenSha: shenSha(chart, rules),
+	}
+}
+
+// shenSha 按起例表查四柱神煞。以地支起例时不查依据所在之柱
+func shenSha(chart models.BaziChart, rules []shenShaRule) []models.ShenSha {
+	pillars := chartPillars(chart)
+	result := make([]models.ShenSha, 0)
+	for _, rule := range rules {
+		for _, basis := range rule.Bases {
+			var key string
+			var source models.PillarPosition
+			switch basis {
+			case basisDayStem:
+				key, source = string(chart.Day.Stem), ""
+			case basisYearStem:
+				key, source = string(chart.Year.Stem), ""
+			case basisDayBranch:
+				key, source = string(chart.Day.Branch), models.PositionDay
+			case basisYearBranch:
+				key, source = string(chart.Year.Branch), models.PositionYear
+			case basisDayPillar:
+				key = string(chart.Day.Stem) + string(chart.Day.Branch)
+				if _, ok := rule.Table[key]; ok {
+					result = append(result, models.ShenSha{
+						Name:   rule.Name,
+						Pillar: models.PositionDay,
+						Branch: chart.Day.Branch,
+						Rule:   fmt.Sprintf("日柱%s", key),
+						Verse:  rule.Verse,
+					})
+				}
+				continue
+			}
+
+			var targets string
+			if basis == basisDayXun {
+				for _, b := range iching.EmptyBranches(chart.Day.Stem, chart.Day.Branch) {
+					targets += string(b)
+				}
+				key = string(chart.Day.Stem) + string(chart.Day.Branch) + "旬"
+				source = models.PositionDay
+			} else {
+				targets = rule.Table[key]
+			}
+			for _, p := range pillars {
+				if p.Position == source || !strings.Contains(targets, string(p.Pillar.Branch)) {
+					continue
+				}
+				result = append(result, models.ShenSha{
+					Name:   rule.Name,
+					Pillar: p.Position,
+					Branch: p.Pillar.Branch,
+					Rule:   fmt.Sprintf("%s%s见%s", basis, key, p.Pillar.Branch),
+					Verse:  rule.Verse,
+				})
+			}
+		}
+	}
+	return result
+}
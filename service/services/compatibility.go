@@ -0,0 +1,227 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/hobbyqhd/yijing/service/models"
+)
+
+// aspectMaxScore 合婚每项评分的满分，四项合计一百分
+const aspectMaxScore = 25
+
+// CompatibilityInput 八字合婚的输入，双方均按八字排盘的输入格式提供
+type CompatibilityInput struct {
+	First  interface{} `json:"first"`  // 甲方出生信息
+	Second interface{} `json:"second"` // 乙方出生信息
+}
+
+// parseCompatibilityInput 解析合婚双方的出生信息
+func parseCompatibilityInput(input interface{}) (*baziBirth, *baziBirth, error) {
+	params := &CompatibilityInput{}
+	if err := decodeInput(input, params); err != nil {
+		return nil, nil, &InputError{fmt.Errorf("合婚参数格式错误")}
+	}
+	if params.First == nil || params.Second == nil {
+		return nil, nil, &InputError{fmt.Errorf("合婚需要提供双方的出生信息")}
+	}
+	first, err := parseBaziInput(params.First)
+	if err != nil {
+		return nil, nil, &InputError{fmt.Errorf("甲方%v", err)}
+	}
+	second, err := parseBaziInput(params.Second)
+	if err != nil {
+		return nil, nil, &InputError{fmt.Errorf("乙方%v", err)}
+	}
+	return first, second, nil
+}
+
+// matchCompatibility 排出双方命盘，从日主关系、夫妻宫、五行互补与生肖四方面评分
+func (s *DivinationService) matchCompatibility(first, second *baziBirth) (*models.CompatibilityReading, error) {
+	a, err := s.generateBaziChart(first)
+	if err != nil {
+		return nil, err
+	}
+	b, err := s.generateBaziChart(second)
+	if err != nil {
+		return nil, err
+	}
+
+	reading := &models.CompatibilityReading{
+		First:  a,
+		Second: b,
+		Aspects: []models.CompatibilityAspect{
+			dayMasterAspect(a, b),
+			branchAspect("夫妻宫", "日支", a.Chart.Day.Branch, b.Chart.Day.Branch),
+			elementAspect(a, b),
+			branchAspect("生肖", "年支", a.Chart.Year.Branch, b.Chart.Year.Branch),
+		},
+	}
+	for _, aspect := range reading.Aspects {
+		reading.Score += aspect.Score
+	}
+	switch {
+	case reading.Score >= 80:
+		reading.Level = "上等"
+	case reading.Score >= 60:
+		reading.Level = "中等"
+	default:
+		reading.Level = "下等"
+	}
+	return reading, nil
+}
+
+// dayMasterAspect 日主关系：天干五合最佳，相生次之，比和又次之，相克最差。
+// 双方提供性别时，另看对方日主是否为己之妻星（男看财）或夫星（女看官）
+func dayMasterAspect(a, b *models.BaziReading) models.CompatibilityAspect {
+	x, y := a.DayMaster, b.DayMaster
+	aspect := models.CompatibilityAspect{Name: "日主关系", MaxScore: aspectMaxScore, Findings: make([]string, 0, 3)}
+	ex, ey := x.Element(), y.Element()
+	switch {
+	case isPair(stemCombinations, string(x), string(y)):
+		aspect.Score = 25
+		aspect.Findings = append(aspect.Findings, fmt.Sprintf("日主%s%s天干五合，情投意合", x, y))
+	case ex.Generates() == ey || ey.Generates() == ex:
+		aspect.Score = 20
+		aspect.Findings = append(aspect.Findings, fmt.Sprintf("日主%s%s与%s%s五行相生，相互扶持", x, ex, y, ey))
+	case ex == ey:
+		aspect.Score = 15
+		aspect.Findings = append(aspect.Findings, fmt.Sprintf("日主同为%s，比和相助，亦易各执己见", ex))
+	default:
+		aspect.Score = 8
+		aspect.Findings = append(aspect.Findings, fmt.Sprintf("日主%s%s与%s%s五行相克，需多包容", x, ex, y, ey))
+	}
+	aspect.Findings = append(aspect.Findings, fmt.Sprintf("乙方日主为甲方之%s，甲方日主为乙方之%s", tenGod(x, y), tenGod(y, x)))
+
+	for _, side := range [2]struct {
+		name         string
+		self, spouse *models.BaziReading
+	}{{"甲方", a, b}, {"乙方", b, a}} {
+		god := tenGod(side.self.DayMaster, side.spouse.DayMaster)
+		var star bool
+		switch side.self.Gender {
+		case models.Male:
+			star = god == models.DirectWealth || god == models.IndirectWealth
+		case models.Female:
+			star = god == models.DirectOfficer || god == models.SevenKillings
+		default:
+			continue
+		}
+		if star {
+			aspect.Score = min(aspect.Score+3, aspectMaxScore)
+			aspect.Findings = append(aspect.Findings, fmt.Sprintf("对方日主正是%s的配偶星（%s）", side.name, god))
+		}
+	}
+	return aspect
+}
+
+// branchAspect 以双方同一柱地支的合、冲、刑、害、破评分，用于夫妻宫（日支）与生肖（年支）
+func branchAspect(name, pillar string, x, y models.Branch) models.CompatibilityAspect {
+	aspect := models.CompatibilityAspect{Name: name, Score: 15, MaxScore: aspectMaxScore, Findings: make([]string, 0, 2)}
+	bx, by := string(x), string(y)
+	note := func(delta int, format string, args ...interface{}) {
+		aspect.Score += delta
+		aspect.Findings = append(aspect.Findings, fmt.Sprintf(format, args...))
+	}
+	if rule, ok := matchPair(sixCombinations, bx, by); ok {
+		note(10, "%s%s%s六合（合化%s），和睦亲密", pillar, bx, by, rule.Element)
+	}
+	if rule, ok := halfHarmony(x, y); ok {
+		note(7, "%s%s，同心协力", pillar, rule.Note)
+	} else if sameHarmonyGroup(x, y) {
+		note(5, "%s%s%s同属三合之局，志趣相投", pillar, bx, by)
+	}
+	if isPair(clashes, bx, by) {
+		note(-10, "%s%s%s六冲，易生争执", pillar, bx, by)
+	}
+	if rule, ok := matchPair(mutualPunishments, bx, by); ok {
+		note(-6, "%s%s%s相刑（%s），相处多摩擦", pillar, bx, by, rule.Note)
+	}
+	if x == y && isSelfPunishment(x) {
+		note(-6, "%s同为%s，自刑", pillar, bx)
+	}
+	if isPair(harms, bx, by) {
+		note(-6, "%s%s%s六害，易生嫌隙", pillar, bx, by)
+	}
+	if isPair(destructions, bx, by) {
+		note(-3, "%s%s%s相破，好事多磨", pillar, bx, by)
+	}
+	if len(aspect.Findings) == 0 {
+		aspect.Findings = append(aspect.Findings, fmt.Sprintf("%s%s%s无合无冲，平稳", pillar, bx, by))
+	}
+	aspect.Score = max(0, min(aspect.Score, aspectMaxScore))
+	return aspect
+}
+
+// elementAspect 五行互补：对方命中己方用神、喜神之五行占比越高，越能补己之所需。
+// 每方向满分为总分的一半，占比达六成即得满分
+func elementAspect(a, b *models.BaziReading) models.CompatibilityAspect {
+	aspect := models.CompatibilityAspect{Name: "五行互补", MaxScore: aspectMaxScore, Findings: make([]string, 0, 2)}
+	total := 0.0
+	for _, side := range [2]struct {
+		name          string
+		self, partner *models.BaziReading
+	}{{"甲方", a, b}, {"乙方", b, a}} {
+		wanted := append([]models.Element{side.self.Analysis.Favorable.Useful}, side.self.Analysis.Favorable.Favorable...)
+		share := 0.0
+		parts := make([]string, 0, len(wanted))
+		for _, score := range side.partner.ElementScores {
+			for _, e := range wanted {
+				if score.Element == e {
+					share += score.Percent
+					parts = append(parts, fmt.Sprintf("%s%.1f%%", e, score.Percent))
+				}
+			}
+		}
+		total += math.Min(share/60, 1) * aspectMaxScore / 2
+		aspect.Findings = append(aspect.Findings, fmt.Sprintf("%s喜用%s，对方命中%s，合计%.1f%%",
+			side.name, joinElements(wanted), strings.Join(parts, "、"), share))
+	}
+	aspect.Score = int(math.Round(total))
+	return aspect
+}
+
+// isPair 两字是否构成规则表中的一对
+func isPair(rules []pairRule, a, b string) bool {
+	_, ok := matchPair(rules, a, b)
+	return ok
+}
+
+// sameHarmonyGroup 两支是否同属一个三合局（不含帝旺，如申辰）
+func sameHarmonyGroup(x, y models.Branch) bool {
+	if x == y {
+		return false
+	}
+	for _, rule := range threeHarmonies {
+		var hits int
+		for _, m := range rule.Members {
+			if m == x || m == y {
+				hits++
+			}
+		}
+		if hits == 2 {
+			return true
+		}
+	}
+	return false
+}
+
+// describeCompatibility 将合婚结果整理为供AI解析引用的文字
+func describeCompatibility(reading *models.CompatibilityReading) string {
+	var b strings.Builder
+	for _, side := range [2]struct {
+		name    string
+		reading *models.BaziReading
+	}{{"甲方", reading.First}, {"乙方", reading.Second}} {
+		c := side.reading.Chart
+		fmt.Fprintf(&b, "%s四柱：%s%s年 %s%s月 %s%s日 %s%s时；%s\n", side.name,
+			c.Year.Stem, c.Year.Branch, c.Month.Stem, c.Month.Branch,
+			c.Day.Stem, c.Day.Branch, c.Hour.Stem, c.Hour.Branch, side.reading.Luck)
+	}
+	for _, aspect := range reading.Aspects {
+		fmt.Fprintf(&b, "%s（%d/%d）：%s\n", aspect.Name, aspect.Score, aspect.MaxScore, strings.Join(aspect.Findings, "；"))
+	}
+	fmt.Fprintf(&b, "合婚总分%d，属%s\n", reading.Score, reading.Level)
+	return b.String()
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hobbyqhd/yijing/service/almanac"
+	"github.com/hobbyqhd/yijing/service/models"
+)
+
+// maxSelectionDays 一次择日最多检索的天数
+const maxSelectionDays = 366
+
+// DateSelectionInput 择日请求
+type DateSelectionInput struct {
+	Activity string      `json:"activity" binding:"required"` // 所择之事，取黄历事项之一，如嫁娶、开业、搬家
+	From     string      `json:"from" binding:"required"`     // 起始日期，格式为2006-01-02
+	To       string      `json:"to" binding:"required"`       // 结束日期（含）
+	Bazi     interface{} `json:"bazi,omitempty"`              // 事主出生信息，格式同八字占卜的input，提供时避开冲克事主之日
+}
+
+// SelectDates 择日：逐日查黄历，忌所择之事或冲事主生年、日柱者不取；
+// 宜所择之事者取之，再按黄道、星宿及与事主地支的合刑害加减分排序
+func (s *AlmanacService) SelectDates(input *DateSelectionInput) (*models.DateSelection, error) {
+	if !isActivity(input.Activity) {
+		return nil, fmt.Errorf("不支持的事项: %s", input.Activity)
+	}
+	from, err := time.Parse(almanacDateLayout, input.From)
+	if err != nil {
+		return nil, fmt.Errorf("起始日期格式应为2006-01-02")
+	}
+	to, err := time.Parse(almanacDateLayout, input.To)
+	if err != nil {
+		return nil, fmt.Errorf("结束日期格式应为2006-01-02")
+	}
+	if to.Before(from) || to.Sub(from) >= maxSelectionDays*24*time.Hour {
+		return nil, fmt.Errorf("日期范围有误，最多检索%d天", maxSelectionDays)
+	}
+
+	selection := &models.DateSelection{
+		Activity: input.Activity,
+		From:     from.Format(almanacDateLayout),
+		To:       to.Format(almanacDateLayout),
+		Days:     make([]models.DayCandidate, 0),
+		Rejected: make([]models.DayCandidate, 0),
+	}
+	if input.Bazi != nil {
+		birth, err := parseBaziInput(input.Bazi)
+		if err != nil {
+			return nil, err
+		}
+		reading, err := (&DivinationService{}).generateBaziChart(birth)
+		if err != nil {
+			return nil, err
+		}
+		selection.YearBranch, selection.DayBranch = reading.Chart.Year.Branch, reading.Chart.Day.Branch
+	}
+
+	// 直接推算各日黄历，不逐日读写缓存：推算本身很快，一次择日可达数百天
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		a, err := almanac.Of(day.Year(), day.Month(), day.Day())
+		if err != nil {
+			return nil, err
+		}
+		candidate := rateDay(a, input.Activity, selection.YearBranch, selection.DayBranch)
+		if candidate.Accepted {
+			selection.Days = append(selection.Days, candidate)
+		} else {
+			selection.Rejected = append(selection.Rejected, candidate)
+		}
+	}
+	sort.SliceStable(selection.Days, func(i, j int) bool {
+		return selection.Days[i].Score > selection.Days[j].Score
+	})
+	return selection, nil
+}
+
+// rateDay 评定一日是否宜做某事，并列出当日不冲事主的黄道吉时
+func rateDay(a *almanac.Almanac, activity string, yearBranch, dayBranch models.Branch) models.DayCandidate {
+	candidate := models.DayCandidate{
+		Date:    a.Calendar.Solar,
+		Lunar:   a.Calendar.LunarText,
+		Ganzhi:  a.Calendar.DayGanzhi,
+		Officer: a.Officer,
+		Spirit:  a.DaySpirit.Name,
+		Score:   50,
+		Reasons: make([]string, 0, 4),
+		Hours:   make([]models.HourCandidate, 0),
+	}
+	reject := func(format string, args ...interface{}) {
+		candidate.Accepted = false
+		candidate.Reasons = append(candidate.Reasons, fmt.Sprintf(format, args...))
+	}
+	adjust := func(delta int, format string, args ...interface{}) {
+		candidate.Score += delta
+		candidate.Reasons = append(candidate.Reasons, fmt.Sprintf("%s（%+d）", fmt.Sprintf(format, args...), delta))
+	}
+
+	candidate.Accepted = true
+	switch {
+	case a.Avoids(activity):
+		reject("%s日忌%s", a.Officer, activity)
+	case a.Suits(activity):
+		adjust(30, "%s日宜%s", a.Officer, activity)
+	default:
+		reject("当日宜忌未列%s，不取", activity)
+	}
+
+	// 事主的生年、日柱之支
+	owners := []struct {
+		name   string
+		branch models.Branch
+	}{{"生年", yearBranch}, {"日柱", dayBranch}}
+	for _, owner := range owners {
+		if owner.branch == "" {
+			continue
+		}
+		if a.ClashesWith(owner.branch) {
+			reject("%s日冲事主%s之%s", a.DayBranch, owner.name, owner.branch)
+			continue
+		}
+		b, o := string(a.DayBranch), string(owner.branch)
+		if isPair(sixCombinations, b, o) {
+			adjust(8, "%s日与事主%s之%s六合", a.DayBranch, owner.name, owner.branch)
+		} else if _, ok := halfHarmony(a.DayBranch, owner.branch); ok || sameHarmonyGroup(a.DayBranch, owner.branch) {
+			adjust(5, "%s日与事主%s之%s三合", a.DayBranch, owner.name, owner.branch)
+		}
+		if rule, ok := matchPair(mutualPunishments, b, o); ok {
+			adjust(-8, "%s日刑事主%s之%s（%s）", a.DayBranch, owner.name, owner.branch, rule.Note)
+		}
+		if isPair(harms, b, o) {
+			adjust(-5, "%s日害事主%s之%s", a.DayBranch, owner.name, owner.branch)
+		}
+	}
+
+	if a.DaySpirit.Yellow {
+		adjust(10, "%s黄道日", a.DaySpirit.Name)
+	} else {
+		adjust(-10, "%s黑道日", a.DaySpirit.Name)
+	}
+	if a.Mansion.Lucky {
+		adjust(5, "%s吉宿值日", a.Mansion.Full)
+	} else {
+		adjust(-5, "%s凶宿值日", a.Mansion.Full)
+	}
+
+	for _, hour := range a.Hours {
+		if !hour.Lucky {
+			continue
+		}
+		if (yearBranch != "" && isPair(clashes, string(hour.Branch), string(yearBranch))) ||
+			(dayBranch != "" && isPair(clashes, string(hour.Branch), string(dayBranch))) {
+			continue
+		}
+		candidate.Hours = append(candidate.Hours, models.HourCandidate{
+			Branch: hour.Branch,
+			Ganzhi: hour.Ganzhi,
+			Range:  hour.Range,
+			Spirit: hour.Spirit.Name,
+		})
+	}
+	return candidate
+}
+
+// isActivity 是否为黄历宜忌中的事项
+func isActivity(activity string) bool {
+	for _, a := range almanac.Activities {
+		if a == activity {
+			return true
+		}
+	}
+	return false
+}
@@ -4,9 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"math/rand"
 	"time"
 
+	"github.com/hobbyqhd/yijing/service/calendar"
 	"github.com/hobbyqhd/yijing/service/config"
 	"github.com/hobbyqhd/yijing/service/models"
 	gopenai "github.com/sashabaranov/go-openai"
@@ -28,6 +28,7 @@ func (s *DivinationService) CreateDivination(userId uint, req *DivinationRequest
 	// 根据占卜类型处理输入数据
 	var result interface{}
 	var err error
+	input := req.Input
 
 	if !models.IsValidDivinationType(req.Type) {
 		return nil, fmt.Errorf("不支持的占卜类型")
@@ -35,33 +36,26 @@ func (s *DivinationService) CreateDivination(userId uint, req *DivinationRequest
 
 	switch models.DivinationType(req.Type) {
 	case models.TypeTarot:
-		result, err = s.drawTarotCards()
+		result, err = s.drawTarotCards(userId, req.Input)
 	case models.TypeBazi:
-		if birthTime, ok := req.Input.(string); ok {
-			parsedTime, err := time.Parse("2006-01-02 15:04:05", birthTime)
-			if err != nil {
-				return nil, fmt.Errorf("出生时间格式错误")
-			}
-			result, err = s.generateBaziChart(parsedTime)
-		} else {
-			return nil, fmt.Errorf("八字占卜需要提供出生时间")
+		var birth *baziBirth
+		if birth, err = parseBaziInput(req.Input); err == nil {
+			result, err = s.generateBaziChart(birth)
 		}
-	case models.TypeZodiac:
-		// 处理星座占卜
-		if zodiacSign, ok := req.Input.(string); ok {
-			result = map[string]interface{}{
-				"sign": zodiacSign,
-				"date": time.Now().Format("2006-01-02"),
-			}
-		} else {
-			return nil, fmt.Errorf("星座占卜需要提供星座信息")
+	case models.TypeCompatibility:
+		var first, second *baziBirth
+		if first, second, err = parseCompatibilityInput(req.Input); err == nil {
+			result, err = s.matchCompatibility(first, second)
 		}
+	case models.TypeZodiac:
+		// 星座由出生时间与出生地推算，不再采信用户自报的星座
+		result, err = zodiacReading(req.Input, time.Now())
+	case models.TypeSynastry:
+		result, err = synastry(req.Input)
 	case models.TypeYijing:
-		// 简单实现：随机生成卦象
-		result = map[string]interface{}{
-			"hexagram":       rand.Intn(64) + 1,
-			"changing_lines": []int{rand.Intn(6) + 1},
-		}
+		var casting *models.YijingCasting
+		result, casting, err = s.castYijing(req.Input)
+		input = casting
 	default:
 		return nil, fmt.Errorf("不支持的占卜类型")
 	}
@@ -71,7 +65,7 @@ func (s *DivinationService) CreateDivination(userId uint, req *DivinationRequest
 	}
 
 	// 获取AI解析
-	analysis, err := s.getAIAnalysis(req)
+	analysis, err := s.getAIAnalysis(req, result)
 	if err != nil {
 		return nil, err
 	}
@@ -82,12 +76,18 @@ func (s *DivinationService) CreateDivination(userId uint, req *DivinationRequest
 		return nil, fmt.Errorf("结果序列化失败: %v", err)
 	}
 
+	// 将输入（含起卦等过程记录）转换为JSON字符串
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("输入序列化失败: %v", err)
+	}
+
 	// 创建占卜记录
 	divination := &models.Divination{
 		UserID:     userId,
 		Type:       models.DivinationType(req.Type),
 		Question:   req.Question,
-		Input:      string(resultJSON),
+		Input:      string(inputJSON),
 		Result:     string(resultJSON),
 		AIAnalysis: analysis,
 		CreatedAt:  time.Now(),
@@ -102,6 +102,18 @@ func (s *DivinationService) CreateDivination(userId uint, req *DivinationRequest
 	return divination, nil
 }
 
+// decodeInput 将请求中的input字段解析为指定的结构
+func decodeInput(input interface{}, v interface{}) error {
+	if input == nil {
+		return nil
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
 func (s *DivinationService) GetUserDivinations(userId uint) ([]*models.Divination, error) {
 	var divinations []*models.Divination
 	if err := config.DB.Where("user_id = ?", userId).Find(&divinations).Error; err != nil {
@@ -110,71 +122,27 @@ func (s *DivinationService) GetUserDivinations(userId uint) ([]*models.Divinatio
 	return divinations, nil
 }
 
-// drawTarotCards 抽取塔罗牌
-func (s *DivinationService) drawTarotCards() (*models.TarotReading, error) {
-	// 定义三张牌阵
-	spread := models.TarotSpread{
-		ID:          1,
-		Name:        "三张牌阵",
-		Description: "过去、现在、未来",
-		Positions:   3,
-	}
-
-	// 创建一个包含所有塔罗牌的切片
-	allCards := make([]models.TarotCard, 78)
-	for i := 0; i < 22; i++ { // 大阿卡纳
-		allCards[i] = models.TarotCard{
-			ID:     i,
-			Type:   "major",
-			Number: i,
-		}
-	}
-	for i := 0; i < 56; i++ { // 小阿卡纳
-		suits := []string{"wands", "cups", "swords", "pentacles"}
-		allCards[i+22] = models.TarotCard{
-			ID:     i + 22,
-			Type:   "minor",
-			Suit:   suits[i/14],
-			Number: i%14 + 1,
-		}
-	}
-
-	// 随机抽取指定数量的牌
-	selectedCards := make([]models.TarotCard, spread.Positions)
-	positions := make([]bool, spread.Positions)
-	for i := 0; i < spread.Positions; i++ {
-		// 随机选择一张牌
-		cardIndex := rand.Intn(len(allCards))
-		selectedCards[i] = allCards[cardIndex]
-		// 移除已选择的牌
-		allCards = append(allCards[:cardIndex], allCards[cardIndex+1:]...)
-		// 随机决定正逆位
-		positions[i] = rand.Intn(2) == 1
-	}
+// generateBaziChart 生成八字命盘
+func (s *DivinationService) generateBaziChart(birth *baziBirth) (*models.BaziReading, error) {
+	birthTime, rule := birth.Time, birth.ZiHourRule
 
-	return &models.TarotReading{
-		Cards:     selectedCards,
-		Positions: positions,
-		Spread:    spread,
-	}, nil
-}
+	// 年柱以立春为界，月柱以十二节为界
+	year, month := calendar.SolarMonth(birthTime)
 
-// generateBaziChart 生成八字命盘
-func (s *DivinationService) generateBaziChart(birthTime time.Time) (*models.BaziReading, error) {
 	// 计算年柱
-	yearStem := s.calculateYearStem(birthTime.Year())
-	yearBranch := s.calculateYearBranch(birthTime.Year())
+	yearStem := s.calculateYearStem(year)
+	yearBranch := s.calculateYearBranch(year)
 
 	// 计算月柱
-	monthStem := s.calculateMonthStem(yearStem, birthTime.Month())
-	monthBranch := s.calculateMonthBranch(birthTime.Month())
+	monthStem := s.calculateMonthStem(yearStem, month)
+	monthBranch := s.calculateMonthBranch(month)
 
 	// 计算日柱
-	dayStem := s.calculateDayStem(birthTime)
-	dayBranch := s.calculateDayBranch(birthTime)
+	dayStem := s.calculateDayStem(birthTime, rule)
+	dayBranch := s.calculateDayBranch(birthTime, rule)
 
-	// 计算时柱
-	hourStem := s.calculateHourStem(dayStem, birthTime.Hour())
+	// 计算时柱，时干总以子时所起之日的日干推算
+	hourStem := s.calculateHourStem(s.calculateDayStem(birthTime, models.ZiHourNextDay), birthTime.Hour())
 	hourBranch := s.calculateHourBranch(birthTime.Hour())
 
 	// 构建八字命盘
@@ -197,46 +165,65 @@ func (s *DivinationService) generateBaziChart(birthTime time.Time) (*models.Bazi
 		},
 	}
 
-	// 计算五行分布
-	elements := s.calculateElements(chart)
+	// 计算五行分布，推定旺衰、格局与用神
+	scores := elementScores(chart)
+	analysis := analyzeBazi(chart, scores)
+
+	reading := &models.BaziReading{
+		Chart:         chart,
+		DayMaster:     dayStem,
+		Luck:          luckSummary(dayStem, analysis),
+		Elements:      elementsByStrength(scores),
+		ZiHourRule:    rule,
+		LunarBirth:    birth.Lunar.String(),
+		SolarTime:     birth.SolarTime,
+		Details:       baziDetails(chart),
+		ElementScores: scores,
+		Analysis:      analysis,
+		Interactions:  chartInteractions(chart),
+		Gender:        birth.Gender,
+		AnnualPillars: annualPillars(birth.AnnualFrom, birth.AnnualTo, birthTime.Year(), chart),
+	}
 
-	return &models.BaziReading{
-		Chart:     chart,
-		DayMaster: dayStem,
-		Elements:  elements,
-	}, nil
+	if birth.Symbols {
+		reading.Symbols = baziSymbols(chart, birth.ShenSha)
+	}
+
+	// 大运的顺逆取决于性别
+	if birth.Gender != "" {
+		reading.LuckCycle = luckCycle(birthTime, birth.Gender, chart)
+	}
+	return reading, nil
 }
 
-// calculateYearStem 计算年干
+// calculateYearStem 计算年干，year为以立春为岁首的年份
 func (s *DivinationService) calculateYearStem(year int) models.Stem {
-	stems := []models.Stem{models.Gui, models.Jia, models.Yi, models.Bing, models.Ding,
-		models.Wu4, models.Ji, models.Geng, models.Xin, models.Ren}
-	return stems[year%10]
+	// 公元4年为甲子年
+	return models.Stems[((year-4)%10+10)%10]
 }
 
-// calculateYearBranch 计算年支
+// calculateYearBranch 计算年支，year为以立春为岁首的年份
 func (s *DivinationService) calculateYearBranch(year int) models.Branch {
-	branches := []models.Branch{models.Hai, models.Zi, models.Chou, models.Yin, models.Mao,
-		models.Chen, models.Si, models.Wu, models.Wei, models.Shen, models.You, models.Xu}
-	return branches[year%12]
+	// 公元4年为甲子年
+	return models.Branches[((year-4)%12+12)%12]
 }
 
-// calculateMonthStem 计算月干
-func (s *DivinationService) calculateMonthStem(yearStem models.Stem, month time.Month) models.Stem {
-	// 根据年干确定月干起始
+// calculateMonthStem 计算月干，month为节气月序（寅月为1）
+func (s *DivinationService) calculateMonthStem(yearStem models.Stem, month int) models.Stem {
+	// 五虎遁：甲己之年丙作首，乙庚之岁戊为头，丙辛必定寻庚起，丁壬壬位顺行流，戊癸何方发，甲寅之上好追求
 	baseIndex := map[models.Stem]int{
-		models.Jia: 0, models.Yi: 2, models.Bing: 4, models.Ding: 6,
-		models.Wu4: 8, models.Ji: 0, models.Geng: 2, models.Xin: 4,
-		models.Ren: 6, models.Gui: 8,
+		models.Jia: 2, models.Yi: 4, models.Bing: 6, models.Ding: 8,
+		models.Wu4: 0, models.Ji: 2, models.Geng: 4, models.Xin: 6,
+		models.Ren: 8, models.Gui: 0,
 	}
 	stems := []models.Stem{models.Jia, models.Yi, models.Bing, models.Ding, models.Wu4,
 		models.Ji, models.Geng, models.Xin, models.Ren, models.Gui}
-	index := (baseIndex[yearStem] + int(month) - 1) % 10
+	index := (baseIndex[yearStem] + month - 1) % 10
 	return stems[index]
 }
 
-// calculateMonthBranch 计算月支
-func (s *DivinationService) calculateMonthBranch(month time.Month) models.Branch {
+// calculateMonthBranch 计算月支，month为节气月序（寅月为1）
+func (s *DivinationService) calculateMonthBranch(month int) models.Branch {
 	branches := []models.Branch{models.Yin, models.Mao, models.Chen, models.Si,
 		models.Wu, models.Wei, models.Shen, models.You, models.Xu, models.Hai,
 		models.Zi, models.Chou}
@@ -244,22 +231,13 @@ func (s *DivinationService) calculateMonthBranch(month time.Month) models.Branch
 }
 
 // calculateDayStem 计算日干
-func (s *DivinationService) calculateDayStem(birthTime time.Time) models.Stem {
-	// 使用简化算法，实际应考虑更复杂的历法计算
-	stems := []models.Stem{models.Jia, models.Yi, models.Bing, models.Ding, models.Wu4,
-		models.Ji, models.Geng, models.Xin, models.Ren, models.Gui}
-	dayNum := birthTime.YearDay()
-	return stems[dayNum%10]
+func (s *DivinationService) calculateDayStem(birthTime time.Time, rule models.ZiHourRule) models.Stem {
+	return models.Stems[dayIndex(birthTime, rule)%10]
 }
 
 // calculateDayBranch 计算日支
-func (s *DivinationService) calculateDayBranch(birthTime time.Time) models.Branch {
-	// 使用简化算法，实际应考虑更复杂的历法计算
-	branches := []models.Branch{models.Zi, models.Chou, models.Yin, models.Mao,
-		models.Chen, models.Si, models.Wu, models.Wei, models.Shen, models.You,
-		models.Xu, models.Hai}
-	dayNum := birthTime.YearDay()
-	return branches[dayNum%12]
+func (s *DivinationService) calculateDayBranch(birthTime time.Time, rule models.ZiHourRule) models.Branch {
+	return models.Branches[dayIndex(birthTime, rule)%12]
 }
 
 // calculateHourStem 计算时干
@@ -272,42 +250,30 @@ func (s *DivinationService) calculateHourStem(dayStem models.Stem, hour int) mod
 	}
 	stems := []models.Stem{models.Jia, models.Yi, models.Bing, models.Ding, models.Wu4,
 		models.Ji, models.Geng, models.Xin, models.Ren, models.Gui}
-	index := (baseIndex[dayStem] + hour/2) % 10
+	index := (baseIndex[dayStem] + (hour+1)/2%12) % 10
 	return stems[index]
 }
 
-// calculateHourBranch 计算时支
+// calculateHourBranch 计算时支，23时至1时为子时
 func (s *DivinationService) calculateHourBranch(hour int) models.Branch {
 	branches := []models.Branch{models.Zi, models.Chou, models.Yin, models.Mao,
 		models.Chen, models.Si, models.Wu, models.Wei, models.Shen, models.You,
 		models.Xu, models.Hai}
-	return branches[(hour/2)%12]
-}
-
-// calculateElements 计算五行分布
-func (s *DivinationService) calculateElements(chart models.BaziChart) []models.Element {
-	elements := make([]models.Element, 0)
-	// 简化实现：仅返回基本五行
-	elements = append(elements,
-		models.Wood,
-		models.Fire,
-		models.Earth,
-		models.Metal,
-		models.Water,
-	)
-	return elements
+	return branches[(hour+1)/2%12]
 }
 
 // getAIAnalysis 获取AI解析
-func (s *DivinationService) getAIAnalysis(req *DivinationRequest) (string, error) {
+func (s *DivinationService) getAIAnalysis(req *DivinationRequest, result interface{}) (string, error) {
 	// 构建提示信息
 	prompt := fmt.Sprintf(
 		"请根据以下占卜信息进行分析：\n"+
 			"占卜类型：%s\n"+
 			"问题：%s\n"+
+			"%s"+
 			"请给出详细的解析和建议。",
 		req.Type,
 		req.Question,
+		describeResult(result),
 	)
 
 	// 调用OpenAI API
@@ -330,3 +296,23 @@ func (s *DivinationService) getAIAnalysis(req *DivinationRequest) (string, error
 
 	return resp.Choices[0].Message.Content, nil
 }
+
+// describeResult 将占卜结果整理为提示信息，使AI能引用卦辞等原文
+func describeResult(result interface{}) string {
+	switch r := result.(type) {
+	case *models.YijingReading:
+		return describeYijing(r)
+	case *models.BaziReading:
+		return describeBazi(r)
+	case *models.CompatibilityReading:
+		return describeCompatibility(r)
+	case *models.TarotReading:
+		return describeTarot(r)
+	case *models.ZodiacReading:
+		return describeZodiac(r)
+	case *models.SynastryReading:
+		return describeSynastry(r)
+	default:
+		return ""
+	}
+}
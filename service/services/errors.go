@@ -0,0 +1,14 @@
+package services
+
+// InputError 由请求参数引起的错误，处理器据此返回400而不是500
+type InputError struct {
+	Err error
+}
+
+func (e *InputError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *InputError) Unwrap() error {
+	return e.Err
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
+	"math"
 	"math/rand"
 	"time"
 
@@ -17,12 +18,22 @@ func NewFortuneService() *FortuneService {
 	return &FortuneService{}
 }
 
-// CalculateFortune 计算用户运势
-func (s *FortuneService) CalculateFortune(userId uint) (*models.Fortune, error) {
-	// 检查今日是否已经计算过运势
+// CalculateFortune 计算用户运势。提供出生信息（格式与星座占卜的input相同）时，
+// 运势指数由当下行运星对本命盘的相位推算，否则随机生成
+func (s *FortuneService) CalculateFortune(userId uint, birth interface{}) (*models.Fortune, error) {
+	// 先解析出生信息，输入有误时不必查库
+	var reading *models.ZodiacReading
+	if birth != nil {
+		var err error
+		if reading, err = zodiacReading(birth, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	// 检查今日是否已经计算过同一种运势，随机生成的结果不能当作行运推算的结果返回
 	today := time.Now().Truncate(24 * time.Hour)
 	var existingFortune models.Fortune
-	result := config.DB.Where("user_id = ? AND date = ?", userId, today).First(&existingFortune)
+	result := config.DB.Where("user_id = ? AND date = ? AND from_transits = ?", userId, today, reading != nil).First(&existingFortune)
 	if result.RowsAffected > 0 {
 		return &existingFortune, nil
 	}
@@ -31,15 +42,22 @@ func (s *FortuneService) CalculateFortune(userId uint) (*models.Fortune, error)
 	fortune := &models.Fortune{
 		UserID:       userId,
 		Date:         today,
-		OverallScore: s.generateScore(),
-		LoveScore:    s.generateScore(),
-		CareerScore:  s.generateScore(),
-		HealthScore:  s.generateScore(),
-		WealthScore:  s.generateScore(),
+		FromTransits: reading != nil,
+	}
+	astrology := ""
+	if reading != nil {
+		s.transitScores(fortune, reading.Transits)
+		astrology = describeZodiac(reading)
+	} else {
+		fortune.OverallScore = s.generateScore()
+		fortune.LoveScore = s.generateScore()
+		fortune.CareerScore = s.generateScore()
+		fortune.HealthScore = s.generateScore()
+		fortune.WealthScore = s.generateScore()
 	}
 
 	// 生成AI分析报告
-	analysis, suggestions, err := s.generateAIAnalysis(fortune)
+	analysis, suggestions, err := s.generateAIAnalysis(fortune, astrology)
 	if err != nil {
 		return nil, fmt.Errorf("生成AI分析报告失败: %v", err)
 	}
@@ -68,8 +86,70 @@ func (s *FortuneService) generateScore() int {
 	return rand.Intn(101)
 }
 
-// generateAIAnalysis 生成AI分析报告和建议
-func (s *FortuneService) generateAIAnalysis(fortune *models.Fortune) (string, string, error) {
+// baseScore 没有行运相位时的运势指数
+const baseScore = 60
+
+// beneficBodies 吉星，行运合相时为吉
+var beneficBodies = map[models.CelestialBody]bool{models.Venus: true, models.Jupiter: true}
+
+// maleficBodies 凶星，行运合相时为凶
+var maleficBodies = map[models.CelestialBody]bool{models.Mars: true, models.Saturn: true, models.Pluto: true}
+
+// fortuneDomains 各项运势所关注的本命点
+var fortuneDomains = struct {
+	Love, Career, Health, Wealth []models.CelestialBody
+}{
+	Love:   []models.CelestialBody{models.Venus, models.Moon, models.Mars},
+	Career: []models.CelestialBody{models.Sun, models.Saturn, models.Jupiter, models.Midheaven},
+	Health: []models.CelestialBody{models.Sun, models.Moon, models.Mars, models.Ascendant},
+	Wealth: []models.CelestialBody{models.Venus, models.Jupiter},
+}
+
+// aspectNature 相位的吉凶：三分、六分相为吉，四分、对分相为凶，合相随行运星而定
+func aspectNature(a models.Aspect) float64 {
+	switch a.Kind {
+	case models.Trine, models.Sextile:
+		return 1
+	case models.Square, models.Opposition:
+		return -1
+	case models.Conjunction:
+		if beneficBodies[a.First] {
+			return 1
+		}
+		if maleficBodies[a.First] {
+			return -1
+		}
+	}
+	return 0
+}
+
+// transitScores 由行运相位推算运势指数：以60分为基准，每个相位按吉凶与紧密程度加减，
+// 分项运势只计落在所关注本命点上的相位
+func (s *FortuneService) transitScores(fortune *models.Fortune, transits []models.Aspect) {
+	score := func(bodies []models.CelestialBody, weight float64) int {
+		total := float64(baseScore)
+		for _, a := range transits {
+			matched := bodies == nil
+			for _, body := range bodies {
+				if a.Second == body {
+					matched = true
+				}
+			}
+			if matched {
+				total += weight * aspectNature(a) * (1 - a.Orb/(transitOrb*2))
+			}
+		}
+		return int(math.Round(math.Max(0, math.Min(100, total))))
+	}
+	fortune.OverallScore = score(nil, 5)
+	fortune.LoveScore = score(fortuneDomains.Love, 10)
+	fortune.CareerScore = score(fortuneDomains.Career, 10)
+	fortune.HealthScore = score(fortuneDomains.Health, 10)
+	fortune.WealthScore = score(fortuneDomains.Wealth, 10)
+}
+
+// generateAIAnalysis 生成AI分析报告和建议，astrology为本命盘与行运相位的说明，可为空
+func (s *FortuneService) generateAIAnalysis(fortune *models.Fortune, astrology string) (string, string, error) {
 	// 构建提示信息
 	prompt := fmt.Sprintf(
 		"请根据以下运势指数进行分析和给出建议：\n"+
@@ -78,12 +158,14 @@ func (s *FortuneService) generateAIAnalysis(fortune *models.Fortune) (string, st
 			"事业运势：%d\n"+
 			"健康运势：%d\n"+
 			"财运指数：%d\n"+
+			"%s"+
 			"请分别给出详细的运势分析和具体的建议。",
 		fortune.OverallScore,
 		fortune.LoveScore,
 		fortune.CareerScore,
 		fortune.HealthScore,
 		fortune.WealthScore,
+		astrology,
 	)
 
 	// 调用OpenAI API
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/hobbyqhd/yijing/service/astro"
+	"github.com/hobbyqhd/yijing/service/models"
+)
+
+// NatalInput 西洋占星本命盘的输入参数，出生信息的字段与八字排盘相同
+type NatalInput struct {
+	BirthTime string   `json:"birthTime"`           // 出生时间（出生地钟表时间），格式为2006-01-02 15:04:05
+	TimeZone  string   `json:"timeZone,omitempty"`  // IANA时区名，默认取出生城市所在时区；只给经纬度时必填；都不给时为Asia/Shanghai
+	DST       *bool    `json:"dst,omitempty"`       // 钟表时间是否为夏令时，不填时按时区数据库判断
+	City      string   `json:"city,omitempty"`      // 出生地城市名（中文或拼音），用于查询经纬度
+	Longitude *float64 `json:"longitude,omitempty"` // 出生地经度，东经为正，优先于城市
+	Latitude  *float64 `json:"latitude,omitempty"`  // 出生地纬度，北纬为正，优先于城市
+	AspectOptions
+}
+
+// astroPlanets 星盘天体对应的行星历表，太阳与月亮另行计算
+var astroPlanets = map[models.CelestialBody]astro.Planet{
+	models.Mercury: astro.Mercury, models.Venus: astro.Venus, models.Mars: astro.Mars,
+	models.Jupiter: astro.Jupiter, models.Saturn: astro.Saturn, models.Uranus: astro.Uranus,
+	models.Neptune: astro.Neptune, models.Pluto: astro.Pluto,
+}
+
+// parseNatalInput 解析本命盘输入，排盘需要出生地的经纬度
+func parseNatalInput(input interface{}) (*NatalInput, *birthPlace, time.Time, error) {
+	params := &NatalInput{}
+	if _, ok := input.(string); ok {
+		return nil, nil, time.Time{}, fmt.Errorf("星座由出生时间推算，请提供出生时间与出生地，而非星座名")
+	}
+	if err := decodeInput(input, params); err != nil {
+		return nil, nil, time.Time{}, fmt.Errorf("星盘参数格式错误")
+	}
+	if params.BirthTime == "" {
+		return nil, nil, time.Time{}, fmt.Errorf("星盘需要提供出生时间")
+	}
+	place, err := resolveBirthPlace(params.City, params.Longitude, params.Latitude, params.TimeZone)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	if place.Longitude == nil || place.Latitude == nil {
+		return nil, nil, time.Time{}, fmt.Errorf("星盘需要提供出生地城市或经纬度")
+	}
+	clock, _, err := parseClockTime(params.BirthTime, place.TimeZone, params.DST)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	if err := checkPlanetYear(clock); err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	return params, place, clock, nil
+}
+
+// checkPlanetYear 行星位置仅在轨道根数的适用年份内可信，超出范围不予排盘
+func checkPlanetYear(t time.Time) error {
+	if year := t.UTC().Year(); year < astro.PlanetMinYear || year > astro.PlanetMaxYear {
+		return fmt.Errorf("行星位置仅适用于%d至%d年", astro.PlanetMinYear, astro.PlanetMaxYear)
+	}
+	return nil
+}
+
+// natalChart 排本命盘：求各天体的视黄经与日行速度、上升点与天顶，
+// 以及普拉西度制与整宫制的宫头
+func natalChart(input interface{}) (*models.NatalChart, error) {
+	params, place, clock, err := parseNatalInput(input)
+	if err != nil {
+		return nil, err
+	}
+	latitude, longitude := *place.Latitude, *place.Longitude
+
+	jd := astro.JulianDay(clock)
+	ascendant, midheaven := astro.Angles(jd, latitude, longitude)
+	chart := &models.NatalChart{
+		ClockTime:  params.BirthTime,
+		TimeZone:   place.TimeZone,
+		UTC:        clock.UTC().Format(time.RFC3339),
+		Place:      place.Name,
+		Longitude:  longitude,
+		Latitude:   latitude,
+		RisingSign: models.SignOf(ascendant),
+		Ascendant:  models.NewChartPoint(ascendant),
+		Midheaven:  models.NewChartPoint(midheaven),
+	}
+
+	wholeSign := astro.WholeSignCusps(ascendant)
+	chart.Houses.WholeSign = houseCusps(wholeSign)
+	placidus, err := astro.PlacidusCusps(jd, latitude, longitude)
+	if err != nil {
+		chart.Notes = append(chart.Notes, err.Error()+"，仅给出整宫制宫位")
+	} else {
+		chart.Houses.Placidus = houseCusps(placidus)
+	}
+
+	for _, position := range planetPositions(astro.ToDynamicalTime(jd)) {
+		position.WholeSignHouse = astro.HouseOf(position.Longitude, wholeSign)
+		if chart.Houses.Placidus != nil {
+			position.House = astro.HouseOf(position.Longitude, placidus)
+		}
+		chart.Planets = append(chart.Planets, position)
+	}
+	chart.SunSign = chart.Planets[0].Sign
+	chart.MoonSign = chart.Planets[1].Sign
+
+	defs, err := activeAspects(params.AspectOptions, 0)
+	if err != nil {
+		return nil, err
+	}
+	chart.Aspects = mutualAspects(natalPoints(chart), defs)
+	return chart, nil
+}
+
+// planetPositions 某一时刻各天体的视黄经、黄纬与日行速度，jde为力学时儒略日
+func planetPositions(jde float64) []models.PlanetPosition {
+	positions := make([]models.PlanetPosition, 0, len(models.CelestialBodies))
+	for _, body := range models.CelestialBodies {
+		lon, lat := bodyPosition(body, jde)
+		// 以前后半日的黄经差求日行度数
+		before, _ := bodyPosition(body, jde-0.5)
+		after, _ := bodyPosition(body, jde+0.5)
+		speed := math.Mod(after-before+540, 360) - 180
+
+		positions = append(positions, models.PlanetPosition{
+			Body:       body,
+			Name:       body.Name(),
+			ChartPoint: models.NewChartPoint(lon),
+			Latitude:   math.Round(lat*10000) / 10000,
+			Speed:      math.Round(speed*10000) / 10000,
+			Retrograde: speed < 0,
+		})
+	}
+	return positions
+}
+
+// bodyPosition 天体的地心视黄经与黄纬（度）
+func bodyPosition(body models.CelestialBody, jde float64) (lon, lat float64) {
+	switch body {
+	case models.Sun:
+		return astro.SunApparentLongitude(jde), 0
+	case models.Moon:
+		lon, lat, _ = astro.MoonPosition(jde)
+		return lon, lat
+	default:
+		lon, lat, _ = astroPlanets[body].Position(jde)
+		return lon, lat
+	}
+}
+
+// houseCusps 将宫头黄经整理为星盘上的点
+func houseCusps(cusps [12]float64) []models.HouseCusp {
+	result := make([]models.HouseCusp, 0, 12)
+	for i, lon := range cusps {
+		result = append(result, models.HouseCusp{House: i + 1, ChartPoint: models.NewChartPoint(lon)})
+	}
+	return result
+}
+
+// formatPoint 以“星座度数”表示星盘上的点，如“白羊座15°30′”
+func formatPoint(p models.ChartPoint) string {
+	degrees := math.Floor(p.Degree)
+	minutes := math.Floor((p.Degree - degrees) * 60)
+	return fmt.Sprintf("%s%.0f°%02.0f′", p.SignName, degrees, minutes)
+}
+
+// describeNatal 将本命盘整理为供AI解析引用的文字
+func describeNatal(chart *models.NatalChart) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "出生：%s（%s），", chart.ClockTime, chart.TimeZone)
+	if chart.Place != "" {
+		fmt.Fprintf(&b, "%s，", chart.Place)
+	}
+	fmt.Fprintf(&b, "经度%.2f，纬度%.2f\n", chart.Longitude, chart.Latitude)
+	fmt.Fprintf(&b, "太阳星座：%s，月亮星座：%s，上升星座：%s\n",
+		chart.SunSign.Name(), chart.MoonSign.Name(), chart.RisingSign.Name())
+	fmt.Fprintf(&b, "上升点：%s，天顶：%s\n", formatPoint(chart.Ascendant), formatPoint(chart.Midheaven))
+	for _, p := range chart.Planets {
+		fmt.Fprintf(&b, "%s：%s", p.Name, formatPoint(p.ChartPoint))
+		if p.Retrograde {
+			b.WriteString("（逆行）")
+		}
+		if p.House > 0 {
+			fmt.Fprintf(&b, "，第%d宫", p.House)
+		} else {
+			fmt.Fprintf(&b, "，整宫制第%d宫", p.WholeSignHouse)
+		}
+		b.WriteString("\n")
+	}
+	if len(chart.Aspects) > 0 {
+		parts := make([]string, 0, len(chart.Aspects))
+		for _, a := range chart.Aspects {
+			parts = append(parts, aspectText(a, "", ""))
+		}
+		fmt.Fprintf(&b, "本命相位：%s\n", strings.Join(parts, "；"))
+	}
+	for _, note := range chart.Notes {
+		fmt.Fprintf(&b, "%s\n", note)
+	}
+	return b.String()
+}
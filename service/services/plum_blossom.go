@@ -0,0 +1,182 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hobbyqhd/yijing/service/calendar"
+	"github.com/hobbyqhd/yijing/service/iching"
+	"github.com/hobbyqhd/yijing/service/models"
+)
+
+// 梅花易数的起卦依据
+const (
+	PlumSourceTime    = "time"    // 以年月日时起卦
+	PlumSourceNumbers = "numbers" // 以所报之数起卦
+	PlumSourceText    = "text"    // 以文字笔画起卦
+)
+
+// castPlumBlossom 梅花易数起卦：以上卦数、下卦数除八取卦，以动爻数除六取爻。
+//   - 时间起卦：年支数加农历月日为上卦数，再加时支数为下卦数与动爻数
+//   - 两数起卦：前数为上卦，后数为下卦，两数之和加时支数取动爻
+//   - 三数起卦：前两数分取上下卦，三数之和取动爻
+//   - 文字起卦：将文字平分两半（字数为奇时上少下多），各以笔画数之和按两数起卦
+func castPlumBlossom(params YijingInput, now time.Time) (*models.PlumBlossomCasting, error) {
+	now = now.In(calendar.ChinaZone)
+	hour := hourBranchNumber(now.Hour())
+
+	casting := &models.PlumBlossomCasting{Source: params.Source}
+	switch params.Source {
+	case PlumSourceTime, "":
+		casting.Source = PlumSourceTime
+		// 子时自前一日23时起，属次日
+		lunar := calendar.SolarToLunar(now.Add(time.Hour))
+		year := branchNumber(lunar.YearBranch())
+		casting.Lunar = fmt.Sprintf("%s%s时", lunar, models.Branches[hour-1])
+		casting.Numbers = []int{year, lunar.Month, lunar.Day, hour}
+		casting.UpperNumber = year + lunar.Month + lunar.Day
+		casting.LowerNumber = casting.UpperNumber + hour
+		casting.MovingNumber = casting.LowerNumber
+	case PlumSourceNumbers:
+		if err := applyPlumNumbers(casting, params.Numbers, hour); err != nil {
+			return nil, err
+		}
+	case PlumSourceText:
+		strokes, err := phraseStrokes(params.Text, params.Strokes)
+		if err != nil {
+			return nil, err
+		}
+		casting.Text = params.Text
+		half := len(strokes) / 2
+		upper, lower := sumInts(strokes[:half]), sumInts(strokes[half:])
+		if err := applyPlumNumbers(casting, []int{upper, lower}, hour); err != nil {
+			return nil, err
+		}
+		casting.Numbers = strokes
+	default:
+		return nil, fmt.Errorf("不支持的梅花易数起卦依据: %s", params.Source)
+	}
+
+	casting.MovingLine = (casting.MovingNumber-1)%6 + 1
+	return casting, nil
+}
+
+// applyPlumNumbers 以两数或三数起卦
+func applyPlumNumbers(casting *models.PlumBlossomCasting, numbers []int, hour int) error {
+	if len(numbers) != 2 && len(numbers) != 3 {
+		return fmt.Errorf("数字起卦需要提供两个或三个数")
+	}
+	for _, n := range numbers {
+		if n <= 0 {
+			return fmt.Errorf("起卦之数必须为正整数")
+		}
+	}
+
+	casting.Numbers = numbers
+	casting.UpperNumber = numbers[0]
+	casting.LowerNumber = numbers[1]
+	if len(numbers) == 3 {
+		casting.MovingNumber = sumInts(numbers)
+	} else {
+		casting.MovingNumber = numbers[0] + numbers[1] + hour
+	}
+	return nil
+}
+
+// phraseStrokes 取文字各字的笔画数，可由调用方直接提供以覆盖内置字表
+func phraseStrokes(text string, provided []int) ([]int, error) {
+	if len(provided) > 0 {
+		if len(provided) < 2 {
+			return nil, fmt.Errorf("文字起卦至少需要两个字")
+		}
+		return provided, nil
+	}
+
+	strokes := make([]int, 0)
+	for _, r := range text {
+		if r == ' ' {
+			continue
+		}
+		count, ok := strokeCounts[r]
+		if !ok {
+			return nil, fmt.Errorf("无法确定“%c”的笔画数，请直接提供各字笔画数", r)
+		}
+		strokes = append(strokes, count)
+	}
+	if len(strokes) < 2 {
+		return nil, fmt.Errorf("文字起卦至少需要两个字")
+	}
+	return strokes, nil
+}
+
+// plumBlossomLines 由上下卦与动爻得出六爻，动爻为老阴或老阳
+func plumBlossomLines(casting *models.PlumBlossomCasting) []models.LineValue {
+	lower := iching.TrigramByXiantian(casting.LowerNumber).Lines()
+	upper := iching.TrigramByXiantian(casting.UpperNumber).Lines()
+
+	lines := make([]models.LineValue, 0, 6)
+	for i, yang := range append(lower, upper...) {
+		moving := i+1 == casting.MovingLine
+		switch {
+		case yang && moving:
+			lines = append(lines, models.OldYang)
+		case yang:
+			lines = append(lines, models.YoungYang)
+		case moving:
+			lines = append(lines, models.OldYin)
+		default:
+			lines = append(lines, models.YoungYin)
+		}
+	}
+	return lines
+}
+
+// bodyUse 判定体用：动爻在下卦则下卦为用、上卦为体，反之亦然
+func bodyUse(hexagram models.Hexagram, movingLine int) *models.BodyUse {
+	result := &models.BodyUse{Body: hexagram.Lower, Use: hexagram.Upper}
+	if movingLine <= 3 {
+		result.Body, result.Use = hexagram.Upper, hexagram.Lower
+	}
+	body, _ := iching.TrigramByName(result.Body)
+	use, _ := iching.TrigramByName(result.Use)
+	result.BodyElement = body.Element
+	result.UseElement = use.Element
+
+	switch {
+	case result.BodyElement == result.UseElement:
+		result.Relation, result.Verdict = "体用比和", "吉，诸事顺遂"
+	case result.UseElement.Generates() == result.BodyElement:
+		result.Relation, result.Verdict = "用生体", "大吉，有进益之喜"
+	case result.BodyElement.Overcomes() == result.UseElement:
+		result.Relation, result.Verdict = "体克用", "吉，事可成但需费力"
+	case result.BodyElement.Generates() == result.UseElement:
+		result.Relation, result.Verdict = "体生用", "小凶，有耗损之忧"
+	default:
+		result.Relation, result.Verdict = "用克体", "凶，诸事不利"
+	}
+	return result
+}
+
+// hourBranchNumber 时辰的地支序数（子为一），23时起为子时
+func hourBranchNumber(hour int) int {
+	return (hour+1)/2%12 + 1
+}
+
+// branchNumber 地支序数（子为一）
+func branchNumber(branch models.Branch) int {
+	for i, b := range models.Branches {
+		if b == branch {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// sumInts 求和
+func sumInts(values []int) int {
+	sum := 0
+	for _, v := range values {
+		sum += v
+	}
+	return sum
+}
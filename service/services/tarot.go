@@ -0,0 +1,125 @@
+package services
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/hobbyqhd/yijing/service/models"
+	"github.com/hobbyqhd/yijing/service/tarot"
+)
+
+// TarotInput 塔罗占卜的输入参数
+type TarotInput struct {
+	Spread       int      `json:"spread"`                  // 牌阵编号，默认为三张牌阵
+	MajorsOnly   bool     `json:"majors_only"`             // 只用二十二张大阿卡纳
+	NoReversals  bool     `json:"no_reversals"`            // 不取逆位，所有牌均为正位
+	ReversalRate *float64 `json:"reversal_rate,omitempty"` // 逆位概率，取值0至1，默认0.5
+	DeckID       uint     `json:"deck_id,omitempty"`       // 使用自己上传的牌组，默认为韦特塔罗
+}
+
+// defaultReversalRate 默认逆位概率
+const defaultReversalRate = 0.5
+
+// drawTarotCards 按所选牌阵洗牌抽牌，每张牌落在对应的牌位上
+func (s *DivinationService) drawTarotCards(userId uint, input interface{}) (*models.TarotReading, error) {
+	var params TarotInput
+	if err := decodeInput(input, &params); err != nil {
+		return nil, fmt.Errorf("塔罗参数格式错误")
+	}
+	if params.Spread == 0 {
+		params.Spread = tarot.DefaultSpread
+	}
+	spread, ok := tarot.SpreadByID(params.Spread)
+	if !ok {
+		return nil, fmt.Errorf("不支持的牌阵: %d", params.Spread)
+	}
+	reversalRate := defaultReversalRate
+	if params.ReversalRate != nil {
+		reversalRate = *params.ReversalRate
+	}
+	if reversalRate < 0 || reversalRate > 1 {
+		return nil, fmt.Errorf("逆位概率应在0至1之间")
+	}
+	if params.NoReversals {
+		reversalRate = 0
+	}
+
+	reading := &models.TarotReading{Spread: spread}
+	deck := tarot.Deck()
+	if params.DeckID != 0 {
+		custom, err := NewTarotService().GetDeck(userId, params.DeckID)
+		if err != nil {
+			return nil, err
+		}
+		deck = append([]models.TarotCard{}, custom.Cards...)
+		reading.Deck = custom.Name
+	}
+	if params.MajorsOnly {
+		deck = tarot.MajorArcana(deck)
+	}
+	if len(deck) < spread.Positions {
+		return nil, fmt.Errorf("牌数不足，%s需要%d张牌，所选牌组只有%d张", spread.Name, spread.Positions, len(deck))
+	}
+
+	// 洗牌后自顶依次发到各牌位
+	rand.Shuffle(len(deck), func(i, j int) { deck[i], deck[j] = deck[j], deck[i] })
+
+	cards := make([]models.DrawnCard, spread.Positions)
+	for i, position := range spread.Layout {
+		cards[i] = models.DrawnCard{
+			Position: position,
+			Card:     deck[i],
+			Upright:  rand.Float64() >= reversalRate,
+		}
+	}
+	reading.Cards = cards
+	reading.Summary = tarot.Summarize(cards, params.DeckID == 0)
+	return reading, nil
+}
+
+// describeTarot 将抽到的牌整理为供AI解析引用的文字
+func describeTarot(reading *models.TarotReading) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "牌阵：%s（%s）\n", reading.Spread.Name, reading.Spread.Description)
+	if reading.Deck != "" {
+		fmt.Fprintf(&b, "牌组：%s（问卜者自定义，牌义以所附含义为准）\n", reading.Deck)
+	}
+	for _, drawn := range reading.Cards {
+		card := drawn.Card
+		orientation, meaning := "正位", card.Upright
+		if !drawn.Upright {
+			orientation, meaning = "逆位", card.Reversed
+			// 自定义牌组可能只提供正位含义
+			if meaning == "" {
+				meaning = "未提供逆位含义，正位含义为：" + card.Upright
+			}
+		}
+		fmt.Fprintf(&b, "第%d张·%s（%s）：%s", drawn.Position.Index, drawn.Position.Name, drawn.Position.Meaning, card.Name)
+		if card.NameEn != "" {
+			fmt.Fprintf(&b, "（%s）", card.NameEn)
+		}
+		b.WriteString(orientation)
+		if len(card.Keywords) > 0 {
+			fmt.Fprintf(&b, "，关键词%s", strings.Join(card.Keywords, "、"))
+		}
+		fmt.Fprintf(&b, "；%s\n", meaning)
+	}
+	describeTarotSummary(&b, reading.Summary)
+	return b.String()
+}
+
+// describeTarotSummary 牌阵概要：张数统计、元素关系与牌组合
+func describeTarotSummary(b *strings.Builder, summary models.TarotSummary) {
+	fmt.Fprintf(b, "大阿卡纳%d张，宫廷牌%d张，数字牌%d张\n", summary.Majors, summary.Courts, summary.Numbers)
+	for _, d := range summary.Dignities {
+		fmt.Fprintf(b, "第%d张%s（%s）与第%d张%s（%s）元素%s\n",
+			d.Positions[0], d.Cards[0], d.Elements[0], d.Positions[1], d.Cards[1], d.Elements[1], d.Relation)
+	}
+	for _, p := range summary.Pairs {
+		fmt.Fprintf(b, "牌组合：%s与%s同现，%s\n", p.Cards[0], p.Cards[1], p.Theme)
+	}
+	for _, note := range summary.Notes {
+		fmt.Fprintf(b, "%s\n", note)
+	}
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hobbyqhd/yijing/service/config"
+	"github.com/hobbyqhd/yijing/service/models"
+	"github.com/hobbyqhd/yijing/service/tarot"
+)
+
+// ErrDeckNotFound 牌组不存在或不属于当前用户
+var ErrDeckNotFound = errors.New("牌组不存在")
+
+type TarotService struct{}
+
+func NewTarotService() *TarotService {
+	return &TarotService{}
+}
+
+// TarotDeckInput 上传自定义牌组的参数
+type TarotDeckInput struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Cards       []models.TarotCard `json:"cards"`
+}
+
+// CreateDeck 校验并保存用户上传的牌组
+func (s *TarotService) CreateDeck(userId uint, input *TarotDeckInput) (*models.TarotDeck, error) {
+	if input.Name == "" {
+		return nil, fmt.Errorf("请填写牌组名称")
+	}
+	cards, err := tarot.NormalizeCustomDeck(input.Cards)
+	if err != nil {
+		return nil, err
+	}
+
+	deck := &models.TarotDeck{
+		UserID:      userId,
+		Name:        input.Name,
+		Description: input.Description,
+		Cards:       cards,
+	}
+	if err := config.DB.Create(deck).Error; err != nil {
+		return nil, fmt.Errorf("保存牌组失败: %v", err)
+	}
+	return deck, nil
+}
+
+// GetUserDecks 获取用户的全部自定义牌组
+func (s *TarotService) GetUserDecks(userId uint) ([]*models.TarotDeck, error) {
+	var decks []*models.TarotDeck
+	if err := config.DB.Where("user_id = ?", userId).Order("id").Find(&decks).Error; err != nil {
+		return nil, fmt.Errorf("获取牌组失败: %v", err)
+	}
+	return decks, nil
+}
+
+// GetDeck 获取用户的某一牌组，不属于该用户的牌组视为不存在
+func (s *TarotService) GetDeck(userId, deckId uint) (*models.TarotDeck, error) {
+	var deck models.TarotDeck
+	result := config.DB.Where("id = ? AND user_id = ?", deckId, userId).Limit(1).Find(&deck)
+	if result.Error != nil {
+		return nil, fmt.Errorf("获取牌组失败: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrDeckNotFound
+	}
+	return &deck, nil
+}
+
+// DeleteDeck 删除用户的某一牌组
+func (s *TarotService) DeleteDeck(userId, deckId uint) error {
+	result := config.DB.Where("id = ? AND user_id = ?", deckId, userId).Delete(&models.TarotDeck{})
+	if result.Error != nil {
+		return fmt.Errorf("删除牌组失败: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrDeckNotFound
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/hobbyqhd/yijing/service/calendar"
+	"github.com/hobbyqhd/yijing/service/iching"
+	"github.com/hobbyqhd/yijing/service/models"
+)
+
+// YijingInput 易经占卜的输入参数
+type YijingInput struct {
+	Method  models.YijingMethod `json:"method"`            // 起卦方式，默认为三钱法
+	Source  string              `json:"source,omitempty"`  // 梅花易数的起卦依据：time、numbers或text
+	Numbers []int               `json:"numbers,omitempty"` // 梅花易数所报之数（两个或三个）
+	Text    string              `json:"text,omitempty"`    // 梅花易数字占所用的文字
+	Strokes []int               `json:"strokes,omitempty"` // 字占各字笔画数，提供时不再查字表
+}
+
+// castYijing 起卦：按所选方式自下而上得出六爻，并返回起卦过程记录
+func (s *DivinationService) castYijing(input interface{}) (*models.YijingReading, *models.YijingCasting, error) {
+	var params YijingInput
+	if err := decodeInput(input, &params); err != nil {
+		return nil, nil, fmt.Errorf("起卦参数格式错误")
+	}
+	if params.Method == "" {
+		params.Method = models.MethodCoins
+	}
+
+	now := time.Now()
+	casting := &models.YijingCasting{Method: params.Method}
+	lines := make([]models.LineValue, 6)
+	switch params.Method {
+	case models.MethodCoins:
+		for i := range lines {
+			toss := tossCoins()
+			casting.Coins = append(casting.Coins, toss)
+			lines[i] = toss.Value
+		}
+	case models.MethodYarrow:
+		for i := range lines {
+			line := divideYarrow()
+			casting.Yarrow = append(casting.Yarrow, line)
+			lines[i] = line.Value
+		}
+	case models.MethodPlumBlossom:
+		plum, err := castPlumBlossom(params, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		casting.PlumBlossom = plum
+		lines = plumBlossomLines(plum)
+	default:
+		return nil, nil, fmt.Errorf("不支持的起卦方式: %s", params.Method)
+	}
+
+	reading := buildYijingReading(params.Method, lines)
+	if casting.PlumBlossom != nil {
+		reading.BodyUse = bodyUse(reading.Primary, casting.PlumBlossom.MovingLine)
+	}
+	dayStem, dayBranch := calendar.DayGanzhi(now)
+	reading.Najia = iching.Najia(lines, dayStem, dayBranch)
+	return reading, casting, nil
+}
+
+// tossCoins 三钱法掷一爻：每枚铜钱字面计二、背面计三，三枚之和即为爻值。
+// 老阴、少阳、少阴、老阳的概率分别为1/8、3/8、3/8、1/8
+func tossCoins() models.CoinToss {
+	toss := models.CoinToss{Coins: make([]int, 3)}
+	sum := 0
+	for i := range toss.Coins {
+		toss.Coins[i] = 2 + rand.Intn(2)
+		sum += toss.Coins[i]
+	}
+	toss.Value = models.LineValue(sum)
+	return toss
+}
+
+// divideYarrow 大衍筮法得一爻：四十九策经三变，余策除以四即为爻值。
+// 老阴、少阳、少阴、老阳的概率分别为1/16、5/16、7/16、3/16
+func divideYarrow() models.YarrowLine {
+	line := models.YarrowLine{Changes: make([]models.YarrowChange, 0, 3)}
+	stalks := 49
+	for i := 0; i < 3; i++ {
+		change := divideStalks(stalks)
+		line.Changes = append(line.Changes, change)
+		stalks -= change.Removed
+	}
+	line.Remaining = stalks
+	line.Value = models.LineValue(stalks / 4)
+	return line
+}
+
+// divideStalks 完成一变：分而为二，挂一，揲之以四，归奇于扐。
+// 左手策数在十六个连续取值中均匀选取，保证揲四之余的四种情况等概率
+func divideStalks(stalks int) models.YarrowChange {
+	left := stalks/2 - 8 + rand.Intn(16)
+	right := stalks - left
+	change := models.YarrowChange{
+		Stalks:         stalks,
+		Left:           left,
+		Right:          right,
+		LeftRemainder:  countByFours(left),
+		RightRemainder: countByFours(right - 1),
+	}
+	change.Removed = 1 + change.LeftRemainder + change.RightRemainder
+	return change
+}
+
+// countByFours 揲之以四，余数为一至四
+func countByFours(stalks int) int {
+	remainder := stalks % 4
+	if remainder == 0 {
+		return 4
+	}
+	return remainder
+}
+
+// buildYijingReading 由六个爻值推出本卦、变爻与之卦，并附上相应经文
+func buildYijingReading(method models.YijingMethod, lines []models.LineValue) *models.YijingReading {
+	primary := make([]bool, len(lines))
+	resulting := make([]bool, len(lines))
+	changing := make([]int, 0)
+	for i, v := range lines {
+		primary[i] = v.IsYang()
+		resulting[i] = primary[i]
+		if v.IsChanging() {
+			resulting[i] = !primary[i]
+			changing = append(changing, i+1)
+		}
+	}
+
+	hexagram := iching.HexagramByLines(primary)
+	reading := &models.YijingReading{
+		Method:        method,
+		Lines:         lines,
+		Primary:       hexagram.Hexagram(),
+		ChangingLines: changing,
+		Derived:       iching.Derive(hexagram),
+		Judgment:      hexagram.Judgment,
+		Image:         hexagram.Image,
+		LineTexts:     changingLineTexts(hexagram, changing),
+	}
+	if len(changing) > 0 {
+		result := iching.HexagramByLines(resulting)
+		resultHexagram := result.Hexagram()
+		reading.Resulting = &resultHexagram
+		reading.ResultingJudgment = &result.Judgment
+	}
+	return reading
+}
+
+// changingLineTexts 取变爻的爻辞；乾坤两卦六爻皆变时取用九、用六
+func changingLineTexts(hexagram *iching.HexagramData, changing []int) []models.LineText {
+	if len(changing) == 6 && hexagram.AllLines != nil {
+		return []models.LineText{*hexagram.AllLines}
+	}
+	texts := make([]models.LineText, 0, len(changing))
+	for _, position := range changing {
+		texts = append(texts, hexagram.Lines[position-1])
+	}
+	return texts
+}
+
+// describeYijing 将卦象与经文整理为供AI解析引用的文字
+func describeYijing(reading *models.YijingReading) string {
+	var b strings.Builder
+	primary := reading.Primary
+	fmt.Fprintf(&b, "本卦：第%d卦 %s（%s，上%s下%s）\n", primary.Number, primary.Name, primary.NameEn, primary.Upper, primary.Lower)
+	fmt.Fprintf(&b, "卦辞：%s\n", reading.Judgment.Zh)
+	fmt.Fprintf(&b, "象曰：%s\n", reading.Image.Zh)
+	if len(reading.LineTexts) == 0 {
+		b.WriteString("变爻：无，以本卦卦辞为断\n")
+	}
+	for _, line := range reading.LineTexts {
+		fmt.Fprintf(&b, "变爻%s：%s\n", line.Name, line.Text.Zh)
+	}
+	if reading.Resulting != nil {
+		resulting := reading.Resulting
+		fmt.Fprintf(&b, "之卦：第%d卦 %s（%s，上%s下%s）\n", resulting.Number, resulting.Name, resulting.NameEn, resulting.Upper, resulting.Lower)
+		fmt.Fprintf(&b, "之卦卦辞：%s\n", reading.ResultingJudgment.Zh)
+	}
+	derived := reading.Derived
+	fmt.Fprintf(&b, "互卦：%s；错卦：%s；综卦：%s\n", derived.Nuclear.Name, derived.Opposite.Name, derived.Inverse.Name)
+	if reading.BodyUse != nil {
+		bu := reading.BodyUse
+		fmt.Fprintf(&b, "体用：体%s（%s）用%s（%s），%s，%s\n", bu.Body, bu.BodyElement, bu.Use, bu.UseElement, bu.Relation, bu.Verdict)
+	}
+	if reading.Najia != nil {
+		describeNajia(&b, reading.Najia)
+	}
+	return b.String()
+}
+
+// describeNajia 将纳甲排盘自上爻至初爻逐爻列出
+func describeNajia(b *strings.Builder, chart *models.NajiaChart) {
+	fmt.Fprintf(b, "纳甲：%s宫（%s）%s卦，%s%s日起卦，旬空%s%s\n",
+		chart.Palace, chart.PalaceElement, chart.Generation, chart.DayStem, chart.DayBranch, chart.Empty[0], chart.Empty[1])
+	for i := len(chart.Lines) - 1; i >= 0; i-- {
+		line := chart.Lines[i]
+		fmt.Fprintf(b, "%s %s%s%s", line.Spirit, line.Relation, line.Branch, line.Element)
+		switch {
+		case line.Shi:
+			b.WriteString(" 世")
+		case line.Ying:
+			b.WriteString(" 应")
+		}
+		if line.Empty {
+			b.WriteString(" 空")
+		}
+		if line.Changed != nil {
+			fmt.Fprintf(b, " 动化%s%s%s", line.Changed.Relation, line.Changed.Branch, line.Changed.Element)
+		}
+		b.WriteString("\n")
+	}
+}
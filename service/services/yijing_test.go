@@ -0,0 +1,112 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hobbyqhd/yijing/service/models"
+)
+
+// trials 统计概率时的抽样次数
+const trials = 40000
+
+// tolerance 抽样频率与理论概率允许的偏差，约为标准差的十倍
+const tolerance = 0.025
+
+func checkFrequencies(t *testing.T, counts map[int]int, want map[int]float64) {
+	t.Helper()
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	for value := range counts {
+		if _, ok := want[value]; !ok {
+			t.Errorf("出现意外取值 %d（%d次）", value, counts[value])
+		}
+	}
+	for value, p := range want {
+		got := float64(counts[value]) / float64(total)
+		if math.Abs(got-p) > tolerance {
+			t.Errorf("取值 %d 的频率为 %.4f，应约为 %.4f", value, got, p)
+		}
+	}
+}
+
+func TestDivideStalks(t *testing.T) {
+	tests := []struct {
+		stalks  int
+		removed map[int]float64 // 一变所去策数的概率
+	}{
+		// 初变：左右余数之和加挂一为五或九，五占四分之三
+		{49, map[int]float64{5: 0.75, 9: 0.25}},
+		// 二变、三变：所去为四或八，各占一半
+		{44, map[int]float64{4: 0.5, 8: 0.5}},
+		{40, map[int]float64{4: 0.5, 8: 0.5}},
+		{36, map[int]float64{4: 0.5, 8: 0.5}},
+		{32, map[int]float64{4: 0.5, 8: 0.5}},
+	}
+	for _, tt := range tests {
+		counts := make(map[int]int)
+		for i := 0; i < trials; i++ {
+			change := divideStalks(tt.stalks)
+			if change.Stalks != tt.stalks || change.Left+change.Right != tt.stalks {
+				t.Fatalf("%d策分为%d与%d", tt.stalks, change.Left, change.Right)
+			}
+			if change.Left < 1 || change.Right < 2 {
+				t.Fatalf("%d策分为%d与%d，右手挂一后无策可揲", tt.stalks, change.Left, change.Right)
+			}
+			for _, r := range []int{change.LeftRemainder, change.RightRemainder} {
+				if r < 1 || r > 4 {
+					t.Fatalf("%d策揲四之余为%d", tt.stalks, r)
+				}
+			}
+			if change.Removed != 1+change.LeftRemainder+change.RightRemainder {
+				t.Fatalf("%d策所去%d不等于挂一与两余之和", tt.stalks, change.Removed)
+			}
+			counts[change.Removed]++
+		}
+		checkFrequencies(t, counts, tt.removed)
+	}
+}
+
+func TestDivideYarrow(t *testing.T) {
+	counts := make(map[int]int)
+	for i := 0; i < trials; i++ {
+		line := divideYarrow()
+		if len(line.Changes) != 3 {
+			t.Fatalf("应有三变，实有%d变", len(line.Changes))
+		}
+		if line.Remaining != int(line.Value)*4 {
+			t.Fatalf("余策%d与爻值%d不符", line.Remaining, line.Value)
+		}
+		counts[int(line.Value)]++
+	}
+	checkFrequencies(t, counts, map[int]float64{
+		int(models.OldYin):    1.0 / 16,
+		int(models.YoungYang): 5.0 / 16,
+		int(models.YoungYin):  7.0 / 16,
+		int(models.OldYang):   3.0 / 16,
+	})
+}
+
+func TestTossCoins(t *testing.T) {
+	counts := make(map[int]int)
+	for i := 0; i < trials; i++ {
+		toss := tossCoins()
+		if len(toss.Coins) != 3 {
+			t.Fatalf("应掷三枚铜钱，实有%d枚", len(toss.Coins))
+		}
+		sum := 0
+		for _, c := range toss.Coins {
+			if c != 2 && c != 3 {
+				t.Fatalf("铜钱计数应为2或3，得%d", c)
+			}
+			sum += c
+		}
+		if int(toss.Value) != sum {
+			t.Fatalf("爻值%d不等于三钱之和%d", toss.Value, sum)
+		}
+		counts[sum]++
+	}
+	checkFrequencies(t, counts, map[int]float64{6: 1.0 / 8, 7: 3.0 / 8, 8: 3.0 / 8, 9: 1.0 / 8})
+}
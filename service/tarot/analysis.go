@@ -0,0 +1,249 @@
+package tarot
+
+import (
+	"fmt"
+
+	"github.com/hobbyqhd/yijing/service/models"
+)
+
+// suitElements 小阿卡纳花色对应的元素
+var suitElements = map[string]string{
+	"wands":     "火",
+	"cups":      "水",
+	"swords":    "风",
+	"pentacles": "土",
+}
+
+// suitNames 花色的中文名
+var suitNames = map[string]string{
+	"wands":     "权杖",
+	"cups":      "圣杯",
+	"swords":    "宝剑",
+	"pentacles": "星币",
+}
+
+// suitThemes 花色占主导时的提示
+var suitThemes = map[string]string{
+	"wands":     "行动、事业与热情",
+	"cups":      "情感、关系与直觉",
+	"swords":    "思虑、沟通与冲突",
+	"pentacles": "金钱、工作与现实物质",
+}
+
+// majorElements 大阿卡纳按黄金黎明会的星象对应所归的元素，以牌号为序
+var majorElements = [22]string{
+	"风", // 愚人：风元素
+	"风", // 魔术师：水星
+	"水", // 女祭司：月亮
+	"土", // 皇后：金星
+	"火", // 皇帝：白羊座
+	"土", // 教皇：金牛座
+	"风", // 恋人：双子座
+	"水", // 战车：巨蟹座
+	"火", // 力量：狮子座
+	"土", // 隐士：处女座
+	"火", // 命运之轮：木星
+	"风", // 正义：天秤座
+	"水", // 倒吊人：水元素
+	"水", // 死神：天蝎座
+	"火", // 节制：射手座
+	"土", // 恶魔：摩羯座
+	"火", // 高塔：火星
+	"风", // 星星：水瓶座
+	"水", // 月亮：双鱼座
+	"火", // 太阳：太阳
+	"火", // 审判：火元素
+	"土", // 世界：土星
+}
+
+// elementRelations 两元素之间的关系，同元素为加强
+var elementRelations = map[[2]string]string{
+	{"火", "风"}: "扶持", {"水", "土"}: "扶持",
+	{"火", "水"}: "削弱", {"风", "土"}: "削弱",
+	{"火", "土"}: "中性", {"风", "水"}: "中性",
+}
+
+// pairRule 牌组合规则，以韦特塔罗的牌序标识两张牌
+type pairRule struct {
+	Cards [2]int
+	Theme string
+}
+
+// pairRules 值得注意的牌组合
+var pairRules = []pairRule{
+	{[2]int{16, 13}, "剧烈而不可逆的终结，旧局面彻底瓦解后方能新生"},
+	{[2]int{16, 17}, "剧变之后的疗愈与希望，灾后重建"},
+	{[2]int{10, 13}, "命运的转折点，一个周期结束、另一个开始"},
+	{[2]int{59, 13}, "已跌至谷底，痛苦的结局之后必有转机"},
+	{[2]int{15, 6}, "受欲望或执念牵制的关系，需分辨爱与依赖"},
+	{[2]int{52, 37}, "感情中的伤痛与和解并存"},
+	{[2]int{6, 37}, "深厚的情感结合，两情相悦"},
+	{[2]int{3, 4}, "阴阳相济，稳定的伴侣或家庭结构"},
+	{[2]int{3, 73}, "家业兴旺，物质与家庭双双丰足"},
+	{[2]int{19, 21}, "圆满的成功，目标达成"},
+	{[2]int{20, 21}, "一个阶段圆满完成，新的召唤随之而来"},
+	{[2]int{18, 2}, "直觉与潜意识极强，真相尚未浮出水面"},
+	{[2]int{9, 12}, "需要独处与暂停，从静观中获得领悟"},
+	{[2]int{1, 22}, "具备开创新事的能力与动力，宜立即行动"},
+	{[2]int{10, 64}, "新的财务机会出现，宜把握时机"},
+}
+
+// ElementOf 牌所属的元素：小阿卡纳按花色，大阿卡纳按星象对应，无从判断时为空
+func ElementOf(card models.TarotCard) string {
+	if card.Type == "major" {
+		if card.Number >= 0 && card.Number < len(majorElements) {
+			return majorElements[card.Number]
+		}
+		return ""
+	}
+	return suitElements[card.Suit]
+}
+
+// elementRelation 两元素之间的关系
+func elementRelation(a, b string) string {
+	if a == b {
+		return "加强"
+	}
+	if relation, ok := elementRelations[[2]string{a, b}]; ok {
+		return relation
+	}
+	return elementRelations[[2]string{b, a}]
+}
+
+// Summarize 统计牌阵中各类牌的张数与主导花色，分析相邻牌位的元素关系。
+// standard表示使用的是韦特塔罗，此时才按牌序匹配牌组合、按牌号定大阿卡纳的元素
+func Summarize(cards []models.DrawnCard, standard bool) models.TarotSummary {
+	summary := models.TarotSummary{
+		Suits:         make([]models.SuitCount, 0, len(Suits)),
+		DominantSuits: make([]string, 0),
+		Pairs:         make([]models.NotablePair, 0),
+	}
+	counts := make(map[string]int)
+	var suitOrder []string
+	reversed := 0
+	for _, drawn := range cards {
+		card := drawn.Card
+		if !drawn.Upright {
+			reversed++
+		}
+		if card.Type == "major" {
+			summary.Majors++
+			continue
+		}
+		if card.Number >= 11 {
+			summary.Courts++
+		} else {
+			summary.Numbers++
+		}
+		if counts[card.Suit] == 0 {
+			suitOrder = append(suitOrder, card.Suit)
+		}
+		counts[card.Suit]++
+	}
+
+	for _, suit := range sortedSuits(suitOrder) {
+		count := counts[suit]
+		summary.Suits = append(summary.Suits, models.SuitCount{Suit: suit, Element: suitElements[suit], Count: count})
+		// 某花色至少两张且占全部牌的三分之一以上即为主导
+		if count >= 2 && count*3 >= len(cards) {
+			summary.DominantSuits = append(summary.DominantSuits, suit)
+		}
+	}
+
+	summary.Dignities = dignities(cards, standard)
+	if standard {
+		summary.Pairs = notablePairs(cards)
+	}
+	summary.Notes = summaryNotes(summary, len(cards), reversed)
+	return summary
+}
+
+// sortedSuits 四花色按牌序在前，自定义牌组的其他花色按出现顺序在后
+func sortedSuits(seen []string) []string {
+	present := make(map[string]bool, len(seen))
+	for _, suit := range seen {
+		present[suit] = true
+	}
+	result := make([]string, 0, len(seen))
+	for _, suit := range Suits {
+		if present[suit] {
+			result = append(result, suit)
+		}
+	}
+	for _, suit := range seen {
+		if _, ok := suitElements[suit]; !ok {
+			result = append(result, suit)
+		}
+	}
+	return result
+}
+
+// dignities 依摆放顺序相邻的两张牌的元素关系
+func dignities(cards []models.DrawnCard, standard bool) []models.ElementalDignity {
+	element := func(card models.TarotCard) string {
+		if card.Type == "major" && !standard {
+			return ""
+		}
+		return ElementOf(card)
+	}
+	result := make([]models.ElementalDignity, 0)
+	for i := 1; i < len(cards); i++ {
+		a, b := cards[i-1], cards[i]
+		ea, eb := element(a.Card), element(b.Card)
+		if ea == "" || eb == "" {
+			continue
+		}
+		result = append(result, models.ElementalDignity{
+			Positions: [2]int{a.Position.Index, b.Position.Index},
+			Cards:     [2]string{a.Card.Name, b.Card.Name},
+			Elements:  [2]string{ea, eb},
+			Relation:  elementRelation(ea, eb),
+		})
+	}
+	return result
+}
+
+// notablePairs 按规则表查找同时出现的牌组合，不论正逆与位置
+func notablePairs(cards []models.DrawnCard) []models.NotablePair {
+	index := make(map[int]models.DrawnCard, len(cards))
+	for _, drawn := range cards {
+		index[drawn.Card.ID] = drawn
+	}
+	result := make([]models.NotablePair, 0)
+	for _, rule := range pairRules {
+		a, okA := index[rule.Cards[0]]
+		b, okB := index[rule.Cards[1]]
+		if !okA || !okB {
+			continue
+		}
+		result = append(result, models.NotablePair{
+			Positions: [2]int{a.Position.Index, b.Position.Index},
+			Cards:     [2]string{a.Card.Name, b.Card.Name},
+			Theme:     rule.Theme,
+		})
+	}
+	return result
+}
+
+// summaryNotes 由张数统计得出的总体提示
+func summaryNotes(summary models.TarotSummary, total, reversed int) []string {
+	notes := make([]string, 0)
+	switch {
+	case total >= 3 && summary.Majors*2 > total:
+		notes = append(notes, "大阿卡纳过半，所问之事关乎人生的重大课题，非一时人力所能左右")
+	case total >= 5 && summary.Majors == 0:
+		notes = append(notes, "没有大阿卡纳，事情多在日常层面，掌握在自己手中")
+	}
+	if summary.Courts >= 2 && summary.Courts*3 >= total {
+		notes = append(notes, "宫廷牌较多，他人或人际关系在此事中起重要作用")
+	}
+	for _, suit := range summary.DominantSuits {
+		if name, ok := suitNames[suit]; ok {
+			notes = append(notes, fmt.Sprintf("%s占主导，重心在%s", name, suitThemes[suit]))
+		}
+	}
+	if total >= 3 && reversed*2 > total {
+		notes = append(notes, "逆位牌过半，能量多受阻滞或转向内在")
+	}
+	return notes
+}
@@ -0,0 +1,101 @@
+// Package tarot 收录韦特（Rider–Waite–Smith）塔罗七十八张牌的中英文资料，
+// 包括牌名、关键词、牌面描述与正逆位含义
+package tarot
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hobbyqhd/yijing/service/config"
+	"github.com/hobbyqhd/yijing/service/models"
+)
+
+//go:embed cards.json
+var cardsJSON []byte
+
+// defaultImageBaseURL 未配置TAROT_IMAGE_BASE_URL时牌面图片的路径前缀
+const defaultImageBaseURL = "/static/tarot/"
+
+// Suits 小阿卡纳四花色，按牌序排列
+var Suits = [4]string{"wands", "cups", "swords", "pentacles"}
+
+// cardData 一张牌的资料，Image为牌面图片文件名
+type cardData struct {
+	models.TarotCard
+	Image string `json:"image"`
+}
+
+// cards 按牌序排列的七十八张牌：大阿卡纳0至21，其后依次为权杖、圣杯、宝剑、星币各十四张
+var cards []cardData
+
+func init() {
+	if err := json.Unmarshal(cardsJSON, &cards); err != nil {
+		panic(fmt.Sprintf("解析塔罗牌资料失败: %v", err))
+	}
+	if err := validate(); err != nil {
+		panic(err)
+	}
+}
+
+// validate 校验牌序、类型与花色
+func validate() error {
+	if len(cards) != 78 {
+		return fmt.Errorf("塔罗牌资料应有78张，实有%d张", len(cards))
+	}
+	for i, c := range cards {
+		if c.ID != i {
+			return fmt.Errorf("第%d条资料的牌序为%d", i+1, c.ID)
+		}
+		if i < 22 {
+			if c.Type != "major" || c.Number != i {
+				return fmt.Errorf("%s应为第%d张大阿卡纳", c.Name, i)
+			}
+			continue
+		}
+		if c.Type != "minor" || c.Suit != Suits[(i-22)/14] || c.Number != (i-22)%14+1 {
+			return fmt.Errorf("%s的花色或牌号有误", c.Name)
+		}
+	}
+	return nil
+}
+
+// Deck 返回整副牌，牌面图片URL以环境变量TAROT_IMAGE_BASE_URL为前缀
+func Deck() []models.TarotCard {
+	base := imageBaseURL()
+	deck := make([]models.TarotCard, len(cards))
+	for i, c := range cards {
+		deck[i] = c.card(base)
+	}
+	return deck
+}
+
+// CardByID 按牌序查找
+func CardByID(id int) (models.TarotCard, bool) {
+	if id < 0 || id >= len(cards) {
+		return models.TarotCard{}, false
+	}
+	return cards[id].card(imageBaseURL()), true
+}
+
+// card 转为占卜结果中的牌，关键词切片另行复制以免调用方修改资料
+func (c cardData) card(base string) models.TarotCard {
+	card := c.TarotCard
+	card.Keywords = append([]string{}, c.Keywords...)
+	card.KeywordsEn = append([]string{}, c.KeywordsEn...)
+	card.ImageURL = base + c.Image
+	return card
+}
+
+// imageBaseURL 牌面图片的路径前缀，保证以斜杠结尾
+func imageBaseURL() string {
+	base := config.GetEnv("TAROT_IMAGE_BASE_URL")
+	if base == "" {
+		return defaultImageBaseURL
+	}
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+	return base
+}
@@ -0,0 +1,52 @@
+package tarot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hobbyqhd/yijing/service/models"
+)
+
+// MaxCustomCards 自定义牌组最多可含的牌数
+const MaxCustomCards = 156
+
+// NormalizeCustomDeck 校验用户上传的牌组：每张牌须有牌名、正位含义与类型（major或minor），
+// 校验通过后按上传顺序重新编号
+func NormalizeCustomDeck(deck []models.TarotCard) ([]models.TarotCard, error) {
+	if len(deck) == 0 {
+		return nil, fmt.Errorf("牌组中没有牌")
+	}
+	if len(deck) > MaxCustomCards {
+		return nil, fmt.Errorf("牌组最多%d张牌，实有%d张", MaxCustomCards, len(deck))
+	}
+	result := make([]models.TarotCard, len(deck))
+	for i, card := range deck {
+		card.Name = strings.TrimSpace(card.Name)
+		if card.Name == "" {
+			return nil, fmt.Errorf("第%d张牌缺少牌名", i+1)
+		}
+		if strings.TrimSpace(card.Upright) == "" {
+			return nil, fmt.Errorf("%s缺少正位含义", card.Name)
+		}
+		if card.Type != "major" && card.Type != "minor" {
+			return nil, fmt.Errorf("%s的类型应为major或minor", card.Name)
+		}
+		if card.Type == "major" {
+			card.Suit = ""
+		}
+		card.ID = i
+		result[i] = card
+	}
+	return result, nil
+}
+
+// MajorArcana 从一副牌中挑出大阿卡纳
+func MajorArcana(deck []models.TarotCard) []models.TarotCard {
+	majors := make([]models.TarotCard, 0, 22)
+	for _, card := range deck {
+		if card.Type == "major" {
+			majors = append(majors, card)
+		}
+	}
+	return majors
+}
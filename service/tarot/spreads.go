@@ -0,0 +1,183 @@
+package tarot
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/hobbyqhd/yijing/service/models"
+)
+
+// 内置牌阵的编号，三张牌阵沿用原有编号1
+const (
+	SpreadThreeCard    = 1
+	SpreadSingle       = 2
+	SpreadCelticCross  = 3
+	SpreadHorseshoe    = 4
+	SpreadRelationship = 5
+	SpreadYearAhead    = 6
+	SpreadDecision     = 7
+)
+
+// DefaultSpread 未指定牌阵时使用三张牌阵
+const DefaultSpread = SpreadThreeCard
+
+var (
+	spreads   = make(map[int]models.TarotSpread)
+	spreadsMu sync.RWMutex
+)
+
+// pos 构造牌位
+func pos(name, nameEn, meaning string, x, y float64) models.SpreadPosition {
+	return models.SpreadPosition{Name: name, NameEn: nameEn, Meaning: meaning, X: x, Y: y}
+}
+
+func init() {
+	crossing := pos("阻碍", "Challenge", "当前面临的阻力或需要克服的问题", 1, 1)
+	crossing.Rotated = true
+
+	builtin := []models.TarotSpread{
+		{
+			ID: SpreadThreeCard, Name: "三张牌阵", NameEn: "Three Card", Description: "过去、现在、未来",
+			Layout: []models.SpreadPosition{
+				pos("过去", "Past", "影响此事的过往经历", 0, 0),
+				pos("现在", "Present", "当前的处境", 1, 0),
+				pos("未来", "Future", "照此发展的走向", 2, 0),
+			},
+		},
+		{
+			ID: SpreadSingle, Name: "单张牌", NameEn: "Single Card", Description: "以一张牌回答问题或作为今日指引",
+			Layout: []models.SpreadPosition{
+				pos("指引", "Guidance", "对所问之事的核心提示", 0, 0),
+			},
+		},
+		{
+			ID: SpreadCelticCross, Name: "凯尔特十字", NameEn: "Celtic Cross", Description: "全面剖析一件事的来龙去脉与内外因素",
+			Layout: []models.SpreadPosition{
+				pos("现状", "Present", "问题的核心与当前处境", 1, 1),
+				crossing,
+				pos("根基", "Foundation", "潜藏在底层的原因", 1, 2),
+				pos("过去", "Recent Past", "正在远去的影响", 0, 1),
+				pos("目标", "Crown", "意识中的目标或可能的最好结果", 1, 0),
+				pos("近未来", "Near Future", "即将到来的发展", 2, 1),
+				pos("自我", "Self", "问卜者的态度与状态", 3.5, 3),
+				pos("环境", "Environment", "他人与外在环境的影响", 3.5, 2),
+				pos("希望与恐惧", "Hopes and Fears", "内心的期待与担忧", 3.5, 1),
+				pos("结果", "Outcome", "最终的结果", 3.5, 0),
+			},
+		},
+		{
+			ID: SpreadHorseshoe, Name: "马蹄铁牌阵", NameEn: "Horseshoe", Description: "呈马蹄形排列的七张牌，分析问题的发展与对策",
+			Layout: []models.SpreadPosition{
+				pos("过去", "Past", "与此事相关的过往", 0, 0),
+				pos("现在", "Present", "当前的状况", 1, 1),
+				pos("隐藏的影响", "Hidden Influences", "尚未察觉的因素", 2, 2),
+				pos("阻碍", "Obstacles", "需要克服的困难", 3, 3),
+				pos("外在影响", "External Influences", "他人与环境的态度", 4, 2),
+				pos("建议", "Advice", "应采取的做法", 5, 1),
+				pos("结果", "Outcome", "可能的结果", 6, 0),
+			},
+		},
+		{
+			ID: SpreadRelationship, Name: "关系牌阵", NameEn: "Relationship", Description: "剖析两人之间的感情或合作关系",
+			Layout: []models.SpreadPosition{
+				pos("你的感受", "You", "你对这段关系的感受与期待", 0, 0),
+				pos("对方的感受", "Partner", "对方对这段关系的感受与期待", 2, 0),
+				pos("关系的基础", "Connection", "维系两人的纽带", 1, 1),
+				pos("过去", "Past", "关系中过往的重要影响", 0, 2),
+				pos("现在", "Present", "关系目前的状态", 1, 2),
+				pos("挑战", "Challenge", "关系面临的问题", 2, 2),
+				pos("走向", "Outcome", "关系的发展方向", 1, 3),
+			},
+		},
+		{
+			ID: SpreadYearAhead, Name: "十二宫年度牌阵", NameEn: "Year Ahead", Description: "按占星十二宫排列，展望未来一年各方面的运势",
+			Layout: yearAheadLayout(),
+		},
+		{
+			ID: SpreadDecision, Name: "二择一牌阵", NameEn: "Decision", Description: "比较两个选项的过程与结果",
+			Layout: []models.SpreadPosition{
+				pos("现状", "Situation", "做决定时的处境与心态", 1, 2),
+				pos("选择甲的过程", "Path A", "选择甲之后的发展", 0, 1),
+				pos("选择乙的过程", "Path B", "选择乙之后的发展", 2, 1),
+				pos("选择甲的结果", "Outcome A", "选择甲的最终结果", 0, 0),
+				pos("选择乙的结果", "Outcome B", "选择乙的最终结果", 2, 0),
+			},
+		},
+	}
+	for _, spread := range builtin {
+		if err := RegisterSpread(spread); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// houses 占星十二宫的牌位
+var houses = [12][3]string{
+	{"第一宫", "First House", "自我、外在形象与新的开始"},
+	{"第二宫", "Second House", "财务、物质与价值观"},
+	{"第三宫", "Third House", "沟通、学习与手足"},
+	{"第四宫", "Fourth House", "家庭、居所与内心根基"},
+	{"第五宫", "Fifth House", "恋爱、创造与娱乐"},
+	{"第六宫", "Sixth House", "工作日常与健康"},
+	{"第七宫", "Seventh House", "伴侣与合作关系"},
+	{"第八宫", "Eighth House", "共有资源、转变与亲密"},
+	{"第九宫", "Ninth House", "远行、信念与深造"},
+	{"第十宫", "Tenth House", "事业、地位与声望"},
+	{"第十一宫", "Eleventh House", "朋友、团体与愿景"},
+	{"第十二宫", "Twelfth House", "潜意识、隐秘与休养"},
+}
+
+// yearAheadLayout 十二宫按逆时针排成一圈，第一宫位于左侧（上升点），中央为年度主题
+func yearAheadLayout() []models.SpreadPosition {
+	layout := make([]models.SpreadPosition, 0, 13)
+	for i, h := range houses {
+		angle := float64(i) * math.Pi / 6
+		x := math.Round((3-3*math.Cos(angle))*100) / 100
+		y := math.Round((3+3*math.Sin(angle))*100) / 100
+		layout = append(layout, pos(h[0], h[1], h[2], x, y))
+	}
+	return append(layout, pos("年度主题", "Theme of the Year", "贯穿全年的核心课题", 3, 3))
+}
+
+// RegisterSpread 登记牌阵。牌位按给定顺序编号，编号已被占用或没有牌位时返回错误
+func RegisterSpread(spread models.TarotSpread) error {
+	if len(spread.Layout) == 0 {
+		return fmt.Errorf("牌阵%s没有牌位", spread.Name)
+	}
+	spreadsMu.Lock()
+	defer spreadsMu.Unlock()
+	if _, ok := spreads[spread.ID]; ok {
+		return fmt.Errorf("牌阵编号%d已被占用", spread.ID)
+	}
+	layout := make([]models.SpreadPosition, len(spread.Layout))
+	for i, p := range spread.Layout {
+		p.Index = i + 1
+		layout[i] = p
+	}
+	spread.Layout = layout
+	spread.Positions = len(layout)
+	spreads[spread.ID] = spread
+	return nil
+}
+
+// SpreadByID 按编号查找牌阵
+func SpreadByID(id int) (models.TarotSpread, bool) {
+	spreadsMu.RLock()
+	defer spreadsMu.RUnlock()
+	spread, ok := spreads[id]
+	return spread, ok
+}
+
+// Spreads 返回全部牌阵，按编号排列
+func Spreads() []models.TarotSpread {
+	spreadsMu.RLock()
+	defer spreadsMu.RUnlock()
+	result := make([]models.TarotSpread, 0, len(spreads))
+	for _, spread := range spreads {
+		result = append(result, spread)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}